@@ -0,0 +1,146 @@
+package warg
+
+import (
+	"slices"
+	"strings"
+)
+
+// FlagRename records a flag's previous name so existing command lines, env vars, and config files
+// keep working after a rename. See [RenamedFrom].
+type FlagRename struct {
+	// Name is the old flag name. It's still accepted on the command line, but not shown in help.
+	Name string
+
+	// EnvVars are the old env var names, looked up after the flag's own env vars.
+	// Names derived from [App.EnvPrefix] and Name are looked up automatically.
+	EnvVars []string
+
+	// ConfigPath is the old config path, searched if the flag's ConfigPath isn't found.
+	ConfigPath string
+}
+
+// FlagRenameOpt is a functional option for configuring a [FlagRename].
+type FlagRenameOpt func(*FlagRename)
+
+// OldEnvVars sets the env var names the flag used before it was renamed.
+func OldEnvVars(names ...string) FlagRenameOpt {
+	return func(fr *FlagRename) {
+		fr.EnvVars = names
+	}
+}
+
+// OldConfigPath sets the config path the flag used before it was renamed.
+func OldConfigPath(path string) FlagRenameOpt {
+	return func(fr *FlagRename) {
+		fr.ConfigPath = path
+	}
+}
+
+// RenamedFrom records that the flag used to be called oldName. oldName still works on the
+// command line, and with opts, old env vars and config paths keep working too.
+//
+// Example usage:
+//
+//	warg.NewCmdFlag(
+//		"--database",
+//		"Database URL",
+//		scalar.String(),
+//		warg.ConfigPath("database"),
+//		warg.RenamedFrom("--db", warg.OldConfigPath("db"), warg.OldEnvVars("DB")),
+//	)
+func RenamedFrom(oldName string, opts ...FlagRenameOpt) FlagOpt {
+	return func(f *Flag) {
+		fr := FlagRename{
+			Name:       oldName,
+			EnvVars:    nil,
+			ConfigPath: "",
+		}
+		for _, opt := range opts {
+			opt(&fr)
+		}
+		f.RenamedFrom = append(f.RenamedFrom, fr)
+	}
+}
+
+// Aliases adds alternative names for the flag, in addition to the one set by [Alias].
+func Aliases(aliases ...string) FlagOpt {
+	return func(f *Flag) {
+		f.Aliases = append(f.Aliases, aliases...)
+	}
+}
+
+// CmdAliases adds alternative names for the command (e.g., "rm" for "remove").
+func CmdAliases(aliases ...string) CmdOpt {
+	return func(cmd *Cmd) {
+		cmd.Aliases = append(cmd.Aliases, aliases...)
+	}
+}
+
+// SectionAliases adds alternative names for the section.
+func SectionAliases(aliases ...string) SectionOpt {
+	return func(sec *Section) {
+		sec.Aliases = append(sec.Aliases, aliases...)
+	}
+}
+
+// helpAliases returns the aliases shown in help: [Flag.Alias] followed by [Flag.Aliases].
+func (fl Flag) helpAliases() []string {
+	var ret []string
+	if fl.Alias != "" {
+		ret = append(ret, fl.Alias)
+	}
+	return append(ret, fl.Aliases...)
+}
+
+// allAliases returns every other name the flag can be passed as: its help aliases, then the names it was renamed from.
+func (fl Flag) allAliases() []string {
+	ret := fl.helpAliases()
+	for _, fr := range fl.RenamedFrom {
+		ret = append(ret, fr.Name)
+	}
+	return ret
+}
+
+// hasName reports whether name is the flag's name (passed as flagName) or one of its aliases.
+func (fl Flag) hasName(flagName string, name string) bool {
+	if name == flagName {
+		return true
+	}
+	for _, alias := range fl.allAliases() {
+		if alias == name {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupName finds a command or section in m by name or alias, returning its name. aliases returns an item's aliases.
+func lookupName[M ~map[string]V, V any](m M, nameOrAlias string, aliases func(V) []string) (string, bool) {
+	if _, exists := m[nameOrAlias]; exists {
+		return nameOrAlias, true
+	}
+	for _, name := range sortedKeys(m) {
+		if slices.Contains(aliases(m[name]), nameOrAlias) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// lookupName finds a command by name or alias, returning its name.
+func (fm CmdMap) lookupName(nameOrAlias string) (string, bool) {
+	return lookupName(fm, nameOrAlias, func(cmd Cmd) []string { return cmd.Aliases })
+}
+
+// lookupName finds a section by name or alias, returning its name.
+func (fm SectionMap) lookupName(nameOrAlias string) (string, bool) {
+	return lookupName(fm, nameOrAlias, func(sec Section) []string { return sec.Aliases })
+}
+
+// withAliases formats a name with its aliases for help output, such as "remove, rm".
+func withAliases(name string, aliases []string) string {
+	if len(aliases) == 0 {
+		return name
+	}
+	return name + ", " + strings.Join(aliases, ", ")
+}
@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"runtime/debug"
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/mattn/go-shellwords"
 	"github.com/reeflective/readline"
@@ -63,6 +65,57 @@ func ConfigFlag(reader config.NewReader, flagMap FlagMap) AppOpt {
 	}
 }
 
+// ConfigFile adds a config file to read flag values from, without requiring a flag to pass it.
+// Call it multiple times to layer config files, such as a system config, a user config
+// (~/.config/app/config.yaml), and a project-local config. Files added later take precedence
+// over files added earlier, and the file passed via [ConfigFlag] (if any) takes precedence over all of them.
+// filePath is expanded like a [path.Path]. Missing files are skipped.
+func ConfigFile(reader config.NewReader, filePath string) AppOpt {
+	return func(app *App) {
+		app.ConfigFiles = append(app.ConfigFiles, ConfigFileSource{
+			NewReader: reader,
+			FilePath:  filePath,
+		})
+	}
+}
+
+// DotEnvFile adds a dotenv file (KEY=VALUE lines, see the dotenv package) whose variables are used
+// like environment variables when resolving [EnvVars] and [EnvPrefix] names. Call it multiple times
+// to layer dotenv files; files added later take precedence, and the real environment takes precedence
+// over all of them. ${VAR} interpolation follows the same precedence (see [go.bbkane.com/warg/dotenv.Parse]).
+// filePath is expanded like a [path.Path]. Missing files are skipped.
+func DotEnvFile(filePath string) AppOpt {
+	return func(a *App) {
+		a.DotEnvFiles = append(a.DotEnvFiles, filePath)
+	}
+}
+
+// EnvPrefix derives env var names for every flag (except the help flag) from the prefix, the command path,
+// and the flag name, so they don't need to be listed with [EnvVars]. Names are uppercased, with
+// dashes replaced by underscores. For example, with EnvPrefix("MYAPP"):
+//
+//   - global flag --db-url reads MYAPP_DB_URL
+//   - --db-url on "db migrate" reads MYAPP_DB_MIGRATE_DB_URL, then MYAPP_DB_URL
+//
+// Derived names are looked up after the flag's own [EnvVars].
+func EnvPrefix(prefix string) AppOpt {
+	return func(a *App) {
+		a.EnvPrefix = prefix
+	}
+}
+
+// ConfigCmds adds an opt-in "config" section with built-in commands for working with config files:
+//
+//   - config init: print a commented config file skeleton (--format yaml|json) generated from
+//     the ConfigPath, HelpShort, and default of every global and command flag
+//   - config validate: check config files for unknown keys and values that don't match their flag's type
+//   - config schema: print a JSON Schema for the config file (see [App.ConfigJSONSchema])
+func ConfigCmds() AppOpt {
+	return func(a *App) {
+		a.ConfigCmds = true
+	}
+}
+
 // HelpFlag customizes the help system by providing custom help command implementations
 // and an optional flag map. Only needed if writing custom help output.
 // helpFlags may be nil (to auto-generate) or a [FlagMap] with exactly one flag that:
@@ -233,20 +286,29 @@ func CompletionsValuesDescriptions(values []completion.Candidate) CompletionsFun
 // [New] panics if [App.Validate] fails (disable with [SkipValidation]).
 func New(name string, version string, rootSection Section, opts ...AppOpt) App {
 	app := App{
-		Name:                    name,
-		RootSection:             rootSection,
-		ConfigFlagName:          "",
-		NewConfigReader:         nil,
-		HelpFlagName:            "",
-		HelpCmds:                make(CmdMap),
-		SkipCompletionCmds:      false,
-		SkipGlobalColorFlag:     false,
-		SkipGlobalTermWidthFlag: false,
-		SkipREPLCmd:             false,
-		SkipValidation:          false,
-		SkipVersionCmd:          false,
-		Version:                 version,
-		GlobalFlags:             make(FlagMap),
+		Name:                        name,
+		RootSection:                 rootSection,
+		AllowPrefixMatching:         false,
+		ConfigFlagName:              "",
+		NewConfigReader:             nil,
+		ConfigFiles:                 nil,
+		ConfigCmds:                  false,
+		ManCmd:                      false,
+		DocsCmd:                     false,
+		EnvPrefix:                   "",
+		DotEnvFiles:                 nil,
+		HelpFlagName:                "",
+		HelpCmds:                    make(CmdMap),
+		SkipCompletionCmds:          false,
+		SkipGlobalColorFlag:         false,
+		SkipGlobalTermWidthFlag:     false,
+		SkipREPLCmd:                 false,
+		SkipValidation:              false,
+		SkipVersionCmd:              false,
+		SignalGracePeriod:           DefaultSignalGracePeriod,
+		ValidateDeprecationRemovals: false,
+		Version:                     version,
+		GlobalFlags:                 make(FlagMap),
 	}
 	for _, opt := range opts {
 		opt(&app)
@@ -311,6 +373,7 @@ func New(name string, version string, rootSection Section, opts ...AppOpt) App {
 					completion.BashCompletionScriptWrite(ctx.Stdout, app.Name)
 					return nil
 				},
+				CmdSkipInheritedMiddlewares(),
 			),
 			NewSubCmd(
 				"zsh",
@@ -319,6 +382,7 @@ func New(name string, version string, rootSection Section, opts ...AppOpt) App {
 					completion.ZshCompletionScriptWrite(ctx.Stdout, app.Name)
 					return nil
 				},
+				CmdSkipInheritedMiddlewares(),
 			),
 			NewSubCmd(
 				"fish",
@@ -327,6 +391,7 @@ func New(name string, version string, rootSection Section, opts ...AppOpt) App {
 					completion.FishCompletionScriptWrite(ctx.Stdout, app.Name)
 					return nil
 				},
+				CmdSkipInheritedMiddlewares(),
 			),
 		)(&app.RootSection)
 	}
@@ -339,14 +404,30 @@ func New(name string, version string, rootSection Section, opts ...AppOpt) App {
 				fmt.Fprintln(ctx.Stdout, ctx.App.Version)
 				return nil
 			},
+			CmdSkipInheritedMiddlewares(),
 		)(&app.RootSection)
 	}
 
+	if app.ConfigCmds {
+		configCmdsSection()(&app.RootSection)
+	}
+
+	if app.ManCmd {
+		manCmd()(&app.RootSection)
+	}
+
+	if app.DocsCmd {
+		docsCmd()(&app.RootSection)
+	}
+
 	if !app.SkipREPLCmd {
 		NewSubCmd(
 			"repl",
 			"Start a REPL to interactively run commands",
 			replCmdAction,
+			CmdHandlesSignals(),
+			// commands run inside the REPL run their own hooks
+			CmdSkipInheritedMiddlewares(),
 		)(&app.RootSection)
 	}
 
@@ -368,6 +449,19 @@ type App struct {
 	// Config
 	ConfigFlagName  string
 	NewConfigReader config.NewReader
+	// ConfigFiles are read in order, with later files taking precedence. See [ConfigFile].
+	ConfigFiles []ConfigFileSource
+	// ConfigCmds adds the "config" section. See [ConfigCmds].
+	ConfigCmds bool
+	// ManCmd adds the "man" command. See [ManCmd].
+	ManCmd bool
+	// DocsCmd adds the "docs" command. See [DocsCmd].
+	DocsCmd bool
+
+	// EnvPrefix derives env var names for flags. See [EnvPrefix].
+	EnvPrefix string
+	// DotEnvFiles are read in order, with later files taking precedence. See [DotEnvFile].
+	DotEnvFiles []string
 
 	// Help
 	HelpFlagName string
@@ -382,7 +476,23 @@ type App struct {
 	SkipValidation          bool
 	SkipVersionCmd          bool
 	SkipREPLCmd             bool
-	Version                 string
+
+	// ValidateDeprecationRemovals makes Validate fail on deprecated items past their RemovedIn version. See [ValidateDeprecationRemovals].
+	ValidateDeprecationRemovals bool
+
+	// AllowPrefixMatching accepts unambiguous prefixes of names. See [AllowPrefixMatching].
+	AllowPrefixMatching bool
+
+	// SignalGracePeriod is how long MustRun waits for an action to return after a signal. See [SignalGracePeriod].
+	SignalGracePeriod time.Duration
+
+	Version string
+}
+
+// ConfigFileSource is a config file added with [ConfigFile].
+type ConfigFileSource struct {
+	NewReader config.NewReader
+	FilePath  string
 }
 
 func parseTermWidth(s string) (string, error) {
@@ -404,6 +514,8 @@ func parseTermWidth(s string) (string, error) {
 // MustRunWithArgs parses and executes the app with the given args (without the program name).
 // Parse errors are printed to stderr with exit code 64 (EX_USAGE).
 // Action errors are printed to stderr with exit code 1.
+// SIGINT or SIGTERM cancels [CmdContext].Context (unless the command uses [CmdHandlesSignals]) and
+// exits with code 130 once the action returns, or after [App.SignalGracePeriod] or a second signal.
 func (app *App) MustRunWithArgs(args []string, opts ...ParseOpt) {
 	// TODO: make colors optional!
 	pr, err := app.Parse(args, opts...)
@@ -415,11 +527,31 @@ func (app *App) MustRunWithArgs(args []string, opts ...ParseOpt) {
 		// https://unix.stackexchange.com/a/254747
 		os.Exit(64)
 	}
-	err = pr.Action(pr.Context)
-	if err != nil {
-		// note that this is user code, so let's not impose styles
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+	if pr.Context.ParseState.CurrentCmd != nil && pr.Context.ParseState.CurrentCmd.HandlesSignals {
+		err = pr.Action(pr.Context)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	outcome, err := runActionWithSignals(pr.Action, pr.Context, app.SignalGracePeriod)
+	switch outcome {
+	case actionOutcome_Returned:
+		if err != nil {
+			// note that this is user code, so let's not impose styles
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	case actionOutcome_Interrupted:
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		os.Exit(130)
+	case actionOutcome_Abandoned:
+		fmt.Fprintln(os.Stderr, "Command did not stop after interrupt; exiting")
+		os.Exit(130)
 	}
 }
 
@@ -484,6 +616,42 @@ func (app *App) MustRun(opts ...ParseOpt) {
 // Satisfiable by [os.LookupEnv] or [LookupMap].
 type LookupEnv func(key string) (string, bool)
 
+// envVarName joins parts into an env var name: uppercased, joined with "_", and with
+// characters other than letters, digits, and underscores replaced by "_".
+func envVarName(parts ...string) string {
+	name := strings.ToUpper(strings.Join(parts, "_"))
+	return strings.Map(func(r rune) rune {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// flagEnvVars returns the env var names to look up for a flag, in order: the flag's own [EnvVars],
+// the env vars it was renamed from (see [RenamedFrom]), then names derived from [App.EnvPrefix]
+// and the flag's current and old names. cmdPath is the command's section path and name,
+// or nil for global flags.
+func (app *App) flagEnvVars(flagName string, fl Flag, cmdPath []string) []string {
+	ret := slices.Clone(fl.EnvVars)
+	names := []string{flagName}
+	for _, fr := range fl.RenamedFrom {
+		ret = append(ret, fr.EnvVars...)
+		names = append(names, fr.Name)
+	}
+	if app.EnvPrefix == "" || flagName == app.HelpFlagName {
+		return ret
+	}
+	for _, name := range names {
+		trimmedName := strings.TrimLeft(name, "-")
+		if len(cmdPath) > 0 {
+			ret = append(ret, envVarName(append(append([]string{app.EnvPrefix}, cmdPath...), trimmedName)...))
+		}
+		ret = append(ret, envVarName(app.EnvPrefix, trimmedName))
+	}
+	return ret
+}
+
 // LookupMap returns a [LookupEnv] backed by a static map. Useful for mocking
 // environment variables in tests.
 func LookupMap(m map[string]string) LookupEnv {
@@ -493,34 +661,41 @@ func LookupMap(m map[string]string) LookupEnv {
 	}
 }
 
-// validateFlags checks that global and command flag names and aliases start with "-" and are unique.
+// validateFlags checks that the flag names, aliases, and negations in flagMaps (global flags, the flags of
+// each section in a command's path, and the command's flags) start with "-" and are unique,
+// and that switch flags hold bool values.
 // It does not need to check the following scenarios:
 //
 //   - global flag names don't collide with global flag names (app will panic when adding the second global flag) - TOOD: ensure there's a test for this
 //   - command flag names in the same command don't collide with each other (app will panic when adding the second command flag) TODO: ensure there's a test for this
 //   - command flag names/aliases don't collide with command flag names/aliases in other commands (since only one command will be run, this is not a problem)
-func validateFlags(
-	globalFlags FlagMap,
-	comFlags FlagMap,
-) error {
+func validateFlags(flagMaps ...FlagMap) error {
 	nameCount := make(map[string]int)
-	for name, fl := range globalFlags {
-		nameCount[name]++
-		if fl.Alias != "" {
-			nameCount[fl.Alias]++
-		}
-	}
-	for name, fl := range comFlags {
-		nameCount[name]++
-		if fl.Alias != "" {
-			nameCount[fl.Alias]++
+	var errs []error
+	for _, fm := range flagMaps {
+		for name, fl := range fm {
+			nameCount[name]++
+			for _, alias := range fl.allAliases() {
+				nameCount[alias]++
+			}
+			if fl.Negatable {
+				if !strings.HasPrefix(name, "--") {
+					errs = append(errs, colerr.NewWrappedf(nil, "Negatable flag names must start with '--': %s", fmt.Sprintf("%#v", name)))
+				}
+				nameCount[negatedName(name)]++
+			}
+			if fl.Switch && fl.EmptyValueConstructor != nil && !isBoolValue(fl.EmptyValueConstructor()) {
+				errs = append(errs, colerr.NewWrappedf(nil, "Switch flags must hold bool values: %s", fmt.Sprintf("%#v", name)))
+			}
 		}
 	}
-	var errs []error
 	for name, count := range nameCount {
 		if !strings.HasPrefix(string(name), "-") {
 			errs = append(errs, colerr.NewWrappedf(nil, "Flag and alias names must start with '-': %s", fmt.Sprintf("%#v", name)))
 		}
+		if strings.Contains(name, "=") {
+			errs = append(errs, colerr.NewWrappedf(nil, "Flag and alias names must not contain '=': %s", fmt.Sprintf("%#v", name)))
+		}
 		if count > 1 {
 			errs = append(errs, colerr.NewWrappedf(nil, "Flag or alias name exists %s times: %s", fmt.Sprintf("%d", count), fmt.Sprintf("%v", name)))
 		}
@@ -528,12 +703,54 @@ func validateFlags(
 	return errors.Join(errs...)
 }
 
+// validatePositionals checks that positional names are unique and don't start with "-",
+// that required positionals come before optional ones, and that only the last positional
+// is variadic (and holds a slice value).
+func validatePositionals(positionals PositionalList) error {
+	var errs []error
+	seen := make(map[string]bool)
+	seenOptional := false
+	for i, pos := range positionals {
+		if pos.Name == "" || strings.HasPrefix(pos.Name, "-") {
+			errs = append(errs, colerr.NewWrappedf(nil, "Positional names must not be empty or start with '-': %s", fmt.Sprintf("%#v", pos.Name)))
+		}
+		if seen[pos.Name] {
+			errs = append(errs, colerr.NewWrappedf(nil, "Positional name exists more than once: %s", pos.Name))
+		}
+		seen[pos.Name] = true
+
+		if pos.Required && seenOptional {
+			errs = append(errs, colerr.NewWrappedf(nil, "Required positionals must come before optional positionals: %s", pos.Name))
+		}
+		if !pos.Required {
+			seenOptional = true
+		}
+
+		if pos.EmptyValueConstructor == nil {
+			errs = append(errs, colerr.NewWrappedf(nil, "Positional must have a value type: %s", pos.Name))
+			continue
+		}
+
+		if pos.Variadic {
+			if i != len(positionals)-1 {
+				errs = append(errs, colerr.NewWrappedf(nil, "Only the last positional may be variadic: %s", pos.Name))
+			}
+			if _, ok := pos.EmptyValueConstructor().(value.SliceValue); !ok {
+				errs = append(errs, colerr.NewWrappedf(nil, "Variadic positionals must be slices: %s", pos.Name))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // Validate checks app for creation errors. It checks:
 //
 //   - the help flag is the right type
 //   - Sections and commands don't start with "-" (needed for parsing)
-//   - Flag names and aliases do start with "-" (needed for parsing)
+//   - Flag names and aliases do start with "-" and don't contain "=" (needed for parsing)
 //   - Flag names and aliases don't collide
+//   - Positionals are unique, ordered, have a value type, and only the last is variadic
+//   - Config paths aren't nested under other config paths (like "db" and "db.url")
 func (app *App) Validate() error {
 
 	// validate --help flag
@@ -579,6 +796,20 @@ func (app *App) Validate() error {
 		}
 	}
 
+	for _, cf := range app.ConfigFiles {
+		if cf.NewReader == nil {
+			return colerr.NewWrappedf(nil, "ConfigFile must have a NewReader: %s", fmt.Sprintf("%v", cf.FilePath))
+		}
+		if cf.FilePath == "" {
+			return colerr.NewWrapped(nil, "ConfigFile must have a non-empty FilePath")
+		}
+	}
+
+	err := app.validateConfigPaths()
+	if err != nil {
+		return err
+	}
+
 	// TODO: check that the default value is in the choices and the choices match app help mappings and that the flag is a scalar
 
 	// NOTE: we need to be able to validate before we parse, and we may not know the app name
@@ -601,16 +832,25 @@ func (app *App) Validate() error {
 		}
 
 		{
-			// child section names should not clash with child command names
+			// child section names and aliases should not clash with child command names and aliases
 			nameCount := make(map[string]int)
-			for name := range flatSec.Sec.Cmds {
+			for name, com := range flatSec.Sec.Cmds {
 				nameCount[string(name)]++
+				for _, alias := range com.Aliases {
+					nameCount[alias]++
+				}
 			}
-			for name := range flatSec.Sec.Sections {
+			for name, sec := range flatSec.Sec.Sections {
 				nameCount[string(name)]++
+				for _, alias := range sec.Aliases {
+					nameCount[alias]++
+				}
 			}
 			errs := []error{}
 			for name, count := range nameCount {
+				if strings.HasPrefix(name, "-") {
+					errs = append(errs, colerr.NewWrappedf(nil, "Command and section aliases must not start with '-': %s", fmt.Sprintf("%#v", name)))
+				}
 				if count > 1 {
 					errs = append(errs, colerr.NewWrappedf(nil, "Command and section name clash: %s", name))
 				}
@@ -628,10 +868,37 @@ func (app *App) Validate() error {
 				return colerr.NewWrappedf(nil, "Command names must not start with '-': %s", fmt.Sprintf("%#v", name))
 			}
 
-			err := validateFlags(app.GlobalFlags, com.Flags)
+			sectionFlagMaps := []FlagMap{app.GlobalFlags}
+			for _, g := range app.sectionFlagGroups(flatSec.Path[1:]) {
+				sectionFlagMaps = append(sectionFlagMaps, g.Flags)
+			}
+			err := validateFlags(append(sectionFlagMaps, com.Flags)...)
 			if err != nil {
 				return err
 			}
+
+			com = app.inheritFlags(flatSec.Path[1:], com)
+			err = validateFlagConstraints(app.GlobalFlags, com.Flags, com.Constraints)
+			if err != nil {
+				return colerr.NewWrappedf(err, "Invalid flag constraints for command: %s", fmt.Sprintf("%#v", name))
+			}
+
+			err = validateFlagRules(app.GlobalFlags, com.Flags, com.Rules)
+			if err != nil {
+				return colerr.NewWrappedf(err, "Invalid flag rules for command: %s", fmt.Sprintf("%#v", name))
+			}
+
+			err = validatePositionals(com.Positionals)
+			if err != nil {
+				return colerr.NewWrappedf(err, "Invalid positionals for command: %s", fmt.Sprintf("%#v", name))
+			}
+		}
+	}
+
+	if app.ValidateDeprecationRemovals {
+		err := app.validateDeprecationRemovals()
+		if err != nil {
+			return colerr.NewWrapped(err, "Deprecated items past their removal version")
 		}
 	}
 
@@ -685,13 +952,13 @@ func (app *App) Complete(args []string, partiallyTypedArg string, opts ...ParseO
 			Type:   completion.Type_ValuesDescriptions,
 			Values: []completion.Candidate{},
 		}
-		for _, name := range s.Cmds.SortedNames() {
+		for _, name := range s.Cmds.visibleSortedNames() {
 			ret.Values = append(ret.Values, completion.Candidate{
 				Name:        string(name),
 				Description: string(s.Cmds[name].HelpShort),
 			})
 		}
-		for _, name := range s.Sections.SortedNames() {
+		for _, name := range s.Sections.visibleSortedNames() {
 			ret.Values = append(ret.Values, completion.Candidate{
 				Name:        string(name),
 				Description: string(s.Sections[name].HelpShort),
@@ -705,15 +972,17 @@ func (app *App) Complete(args []string, partiallyTypedArg string, opts ...ParseO
 	}
 
 	// Finish the parse!
-	err = app.resolveFlags(parseState.CurrentCmd, parseState.FlagValues, parseOpts.LookupEnv, parseState.UnsetFlagNames)
+	err = app.resolveFlags(&parseState, parseOpts.LookupEnv)
 	if err != nil {
 		return nil, colerr.NewWrapped(err, "Unexpected resolveFlags err")
 	}
 	cmdContext := CmdContext{
 		App:           app,
+		Context:       parseOpts.Context,
 		ParseMetadata: parseOpts.ParseMetadata,
 		Flags:         parseState.FlagValues.ToPassedFlags(),
 		ForwardedArgs: parseState.CurrentCmdForwardedArgs, // should always be nil during completions as completions occur at the end
+		Positionals:   parseState.PositionalValues.ToPassedFlags(),
 		ParseState:    &parseState,
 		Stderr:        parseOpts.Stderr,
 		Stdin:         parseOpts.Stdin,
@@ -722,6 +991,14 @@ func (app *App) Complete(args []string, partiallyTypedArg string, opts ...ParseO
 
 	switch parseState.ParseArgState {
 	case ParseArgState_WantFlagNameOrEnd:
+		// --flag=<partial> completes the value of --flag
+		if name, _, found := strings.Cut(partiallyTypedArg, "="); found && strings.HasPrefix(name, "-") {
+			return flagEqualsValueCompletions(cmdContext, name, partiallyTypedArg)
+		}
+		// prefer suggesting positionals unless the user has started typing a flag
+		if pos := parseState.NextPositional(); pos != nil && !strings.HasPrefix(partiallyTypedArg, "-") {
+			return pos.Completions(cmdContext)
+		}
 		return cmdCompletions(cmdContext)
 	case ParseArgState_WantFlagValue:
 		return parseState.CurrentFlag.Completions(cmdContext)
@@ -732,6 +1009,86 @@ func (app *App) Complete(args []string, partiallyTypedArg string, opts ...ParseO
 	}
 }
 
+// flagEqualsValueCompletions completes the value part of a --flag=value arg. Candidates keep the "--flag="
+// prefix so shells filter them against the whole partially typed arg. Shells can't complete paths after
+// the prefix, so path completions are listed here instead.
+func flagEqualsValueCompletions(cmdCtx CmdContext, nameOrAlias string, partiallyTypedArg string) (*completion.Candidates, error) {
+	var flagName string
+	var fl *Flag
+	for _, fm := range []FlagMap{cmdCtx.App.GlobalFlags, cmdCtx.ParseState.CurrentCmd.Flags} {
+		for name, f := range fm {
+			if f.hasName(name, nameOrAlias) {
+				flagName = name
+				fl = &f
+			}
+		}
+	}
+	if fl == nil {
+		return &completion.Candidates{
+			Type:   completion.Type_None,
+			Values: nil,
+		}, nil
+	}
+
+	cmdCtx.ParseState.CurrentFlagName = flagName
+	cmdCtx.ParseState.CurrentFlag = fl
+	candidates, err := fl.Completions(cmdCtx)
+	if err != nil {
+		return nil, err
+	}
+	prefix := nameOrAlias + "="
+	//nolint:exhaustive  // only path completions need the shell's help
+	switch candidates.Type {
+	case completion.Type_Directories:
+		return pathCompletions(prefix, strings.TrimPrefix(partiallyTypedArg, prefix), true), nil
+	case completion.Type_DirectoriesFiles:
+		return pathCompletions(prefix, strings.TrimPrefix(partiallyTypedArg, prefix), false), nil
+	}
+	for i := range candidates.Values {
+		candidates.Values[i].Name = prefix + candidates.Values[i].Name
+	}
+	return candidates, nil
+}
+
+// pathCompletions lists the entries in partialPath's directory that start with its last element, each
+// prefixed with prefix. Directories end in "/" and hidden entries are only listed once a "." is typed.
+// If dirsOnly is true, files are skipped.
+func pathCompletions(prefix string, partialPath string, dirsOnly bool) *completion.Candidates {
+	dir, base := filepath.Split(partialPath)
+	readDir := dir
+	if readDir == "" {
+		readDir = "."
+	}
+	entries, err := os.ReadDir(readDir)
+	if err != nil {
+		// nothing to suggest in a directory that doesn't exist or can't be read
+		return &completion.Candidates{
+			Type:   completion.Type_None,
+			Values: nil,
+		}
+	}
+	candidates := &completion.Candidates{
+		Type:   completion.Type_Values,
+		Values: []completion.Candidate{},
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, base) || (strings.HasPrefix(name, ".") && !strings.HasPrefix(base, ".")) {
+			continue
+		}
+		if entry.IsDir() {
+			name += string(filepath.Separator)
+		} else if dirsOnly {
+			continue
+		}
+		candidates.Values = append(candidates.Values, completion.Candidate{
+			Name:        prefix + dir + name,
+			Description: "",
+		})
+	}
+	return candidates
+}
+
 func cmdCompletions(cmdCtx CmdContext) (*completion.Candidates, error) {
 	// FZF (or maybe zsh) auto-sorts by alphabetical order, so no need to get fancy with the following ideas
 	//  - if the flag is required and is not set, suggest it first
@@ -742,7 +1099,7 @@ func cmdCompletions(cmdCtx CmdContext) (*completion.Candidates, error) {
 		Values: []completion.Candidate{},
 	}
 	// command flags
-	for _, name := range cmdCtx.ParseState.CurrentCmd.Flags.SortedNames() {
+	for _, name := range cmdCtx.ParseState.CurrentCmd.Flags.visibleSortedNames() {
 		// scalar flags set by passed arg can't be appended to or overridden, so don't suggest them
 		val, isScalar := cmdCtx.ParseState.FlagValues[name].(value.ScalarValue)
 		if isScalar && val.UpdatedBy() == value.UpdatedByFlag {
@@ -760,13 +1117,19 @@ func cmdCompletions(cmdCtx CmdContext) (*completion.Candidates, error) {
 			Name:        string(name),
 			Description: string(cmdCtx.ParseState.CurrentCmd.Flags[name].HelpShort) + valStr,
 		})
+		if cmdCtx.ParseState.CurrentCmd.Flags[name].Negatable {
+			candidates.Values = append(candidates.Values, negatedFlagCandidate(name))
+		}
 	}
 	// global flags
-	for _, name := range cmdCtx.App.GlobalFlags.SortedNames() {
+	for _, name := range cmdCtx.App.GlobalFlags.visibleSortedNames() {
 		candidates.Values = append(candidates.Values, completion.Candidate{
 			Name:        string(name),
 			Description: string(cmdCtx.App.GlobalFlags[name].HelpShort),
 		})
+		if cmdCtx.App.GlobalFlags[name].Negatable {
+			candidates.Values = append(candidates.Values, negatedFlagCandidate(name))
+		}
 	}
 
 	// AllowForwardedArgs
@@ -780,6 +1143,13 @@ func cmdCompletions(cmdCtx CmdContext) (*completion.Candidates, error) {
 	return candidates, nil
 }
 
+func negatedFlagCandidate(name string) completion.Candidate {
+	return completion.Candidate{
+		Name:        negatedName(name),
+		Description: "Set " + name + " to false",
+	}
+}
+
 // Examples to get an intution of this:
 //
 // butler >>> hi
@@ -873,20 +1243,33 @@ func replCmdAction(cmdCtx CmdContext) error {
 		if err != nil {
 			return colerr.NewWrapped(err, "Could not read line")
 		}
-		words, err := shellwords.Parse(line)
-		if err != nil {
-			fmt.Fprintf(cmdCtx.Stderr, "could not parse line: %v\n", err)
-			continue
-		}
-		pr, err := cmdCtx.App.Parse(words)
-		if err != nil {
-			fmt.Fprintf(cmdCtx.Stderr, "could not parse args: %v\n", err)
-			continue
-		}
-		err = pr.Action(pr.Context)
+		replRunLine(cmdCtx, line)
+	}
+}
+
+// replRunLine parses and runs one line read by the REPL, printing errors to cmdCtx.Stderr.
+// The command runs with its own hooks, as the repl command itself doesn't run any.
+func replRunLine(cmdCtx CmdContext, line string) {
+	words, err := shellwords.Parse(line)
+	if err != nil {
+		fmt.Fprintf(cmdCtx.Stderr, "could not parse line: %v\n", err)
+		return
+	}
+	pr, err := cmdCtx.App.Parse(words, ParseWithContext(cmdCtx.Context))
+	if err != nil {
+		fmt.Fprintf(cmdCtx.Stderr, "could not parse args: %v\n", err)
+		return
+	}
+	// interrupting a command cancels it and returns to the prompt. A command that doesn't stop
+	// would keep writing to the REPL's output, so exit like MustRunWithArgs does.
+	outcome, err := runActionWithSignals(pr.Action, pr.Context, cmdCtx.App.SignalGracePeriod)
+	switch outcome {
+	case actionOutcome_Returned, actionOutcome_Interrupted:
 		if err != nil {
 			fmt.Fprintf(cmdCtx.Stderr, "error running command: %v\n", err)
-			continue
 		}
+	case actionOutcome_Abandoned:
+		fmt.Fprintln(cmdCtx.Stderr, "command did not stop after interrupt; exiting")
+		os.Exit(130)
 	}
 }
@@ -7,6 +7,8 @@ import (
 	"testing"
 
 	"go.bbkane.com/warg"
+	"go.bbkane.com/warg/config/yamlreader"
+	"go.bbkane.com/warg/value/dict"
 	"go.bbkane.com/warg/value/scalar"
 	"go.bbkane.com/warg/value/slice"
 )
@@ -371,3 +373,548 @@ func TestFlagGroupHelp(t *testing.T) {
 		})
 	}
 }
+
+func TestPositionalHelp(t *testing.T) {
+	updateGolden := os.Getenv("WARG_TEST_UPDATE_GOLDEN") != ""
+	tests := []struct {
+		name   string
+		args   []string
+		lookup warg.LookupEnv
+	}{
+		{
+			name:   "detailedCommand",
+			args:   []string{"copy", "dst", "--help", "detailed"},
+			lookup: warg.LookupMap(nil),
+		},
+		{
+			name:   "compactCommand",
+			args:   []string{"copy", "--help", "compact"},
+			lookup: warg.LookupMap(nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := warg.New(
+				"butler",
+				"v1.0.0",
+				warg.NewSection(
+					"A virtual assistant",
+					warg.NewSubCmd(
+						"copy",
+						"Copy files",
+						warg.Unimplemented(),
+						warg.CmdPositional(
+							"DST",
+							"Destination directory",
+							scalar.String(),
+							warg.PositionalRequired(),
+						),
+						warg.CmdPositional(
+							"SRCS",
+							"Files to copy",
+							slice.String(slice.Default([]string{"."})),
+							warg.PositionalVariadic(),
+						),
+						warg.NewCmdFlag(
+							"--force",
+							"Overwrite existing files",
+							scalar.Bool(scalar.Default(false)),
+						),
+					),
+				),
+				warg.SkipAll(),
+			)
+			warg.GoldenTest(
+				t,
+				warg.GoldenTestArgs{
+					App:             &app,
+					UpdateGolden:    updateGolden,
+					ExpectActionErr: false,
+					Args:            tt.args,
+				},
+				warg.ParseWithLookupEnv(tt.lookup),
+			)
+		})
+	}
+}
+
+func TestSwitchHelp(t *testing.T) {
+	updateGolden := os.Getenv("WARG_TEST_UPDATE_GOLDEN") != ""
+	tests := []struct {
+		name   string
+		args   []string
+		lookup warg.LookupEnv
+	}{
+		{
+			name:   "detailedCommand",
+			args:   []string{"run", "--no-cache", "--help", "detailed"},
+			lookup: warg.LookupMap(nil),
+		},
+		{
+			name:   "compactCommand",
+			args:   []string{"run", "-v", "--help", "compact"},
+			lookup: warg.LookupMap(nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := warg.New(
+				"builder",
+				"v1.0.0",
+				warg.NewSection(
+					"Build things",
+					warg.NewSubCmd(
+						"run",
+						"Run the build",
+						warg.Unimplemented(),
+						warg.NewCmdFlag(
+							"--cache",
+							"Use the build cache",
+							scalar.Bool(scalar.Default(true)),
+							warg.Negatable(),
+						),
+						warg.NewCmdFlag(
+							"--verbose",
+							"Print more output",
+							scalar.Bool(),
+							warg.Alias("-v"),
+							warg.Switch(),
+						),
+					),
+				),
+				warg.SkipAll(),
+			)
+			warg.GoldenTest(
+				t,
+				warg.GoldenTestArgs{
+					App:             &app,
+					UpdateGolden:    updateGolden,
+					ExpectActionErr: false,
+					Args:            tt.args,
+				},
+				warg.ParseWithLookupEnv(tt.lookup),
+			)
+		})
+	}
+}
+
+func TestExplainHelp(t *testing.T) {
+	updateGolden := os.Getenv("WARG_TEST_UPDATE_GOLDEN") != ""
+	tests := []struct {
+		name   string
+		args   []string
+		lookup warg.LookupEnv
+	}{
+		{
+			name:   "command",
+			args:   []string{"fetch", "--tag", "a", "--url=example.com", "--tag", "b", "--help", "explain"},
+			lookup: warg.LookupMap(map[string]string{"FETCH_RETRIES": "3"}),
+		},
+		{
+			name:   "positionals",
+			args:   []string{"fetch", "downloads", "--help", "explain"},
+			lookup: warg.LookupMap(nil),
+		},
+		{
+			name:   "section",
+			args:   []string{"--help", "explain"},
+			lookup: warg.LookupMap(nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := warg.New(
+				"fetcher",
+				"v1.0.0",
+				warg.NewSection(
+					"Fetch things",
+					warg.NewSubCmd(
+						"fetch",
+						"Fetch a URL",
+						warg.Unimplemented(),
+						warg.NewCmdFlag(
+							"--retries",
+							"Number of retries",
+							scalar.Int(scalar.Default(1)),
+							warg.EnvVars("FETCH_RETRIES"),
+						),
+						warg.NewCmdFlag(
+							"--tag",
+							"Tags to add",
+							slice.String(),
+						),
+						warg.NewCmdFlag(
+							"--timeout",
+							"Timeout in seconds",
+							scalar.Int(),
+							warg.ConfigPath("timeout"),
+						),
+						warg.NewCmdFlag(
+							"--url",
+							"URL to fetch",
+							scalar.String(),
+						),
+						warg.NewCmdFlag(
+							"--user-agent",
+							"User agent",
+							scalar.String(),
+						),
+						warg.CmdPositional("DIR", "Directory to save to", scalar.Path()),
+						warg.CmdPositional("NAME", "File name to save as", scalar.String(scalar.Default("index.html"))),
+						warg.CmdPositional("EXTRA", "More URLs to fetch", slice.String(), warg.PositionalVariadic()),
+					),
+				),
+				warg.ConfigFile(yamlreader.New, "testdata/TestExplainHelp/config.yaml"),
+				warg.SkipAll(),
+			)
+			warg.GoldenTest(
+				t,
+				warg.GoldenTestArgs{
+					App:             &app,
+					UpdateGolden:    updateGolden,
+					ExpectActionErr: false,
+					Args:            tt.args,
+				},
+				warg.ParseWithLookupEnv(tt.lookup),
+			)
+		})
+	}
+}
+
+func TestEnvPrefixHelp(t *testing.T) {
+	updateGolden := os.Getenv("WARG_TEST_UPDATE_GOLDEN") != ""
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{
+			name: "detailedCommand",
+			args: []string{"db", "migrate", "--help", "detailed"},
+		},
+		{
+			name: "compactCommand",
+			args: []string{"db", "migrate", "--help", "compact"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := warg.New(
+				"myapp",
+				"v1.0.0",
+				warg.NewSection(
+					"Manage my app",
+					warg.NewSubSection(
+						"db",
+						"Database commands",
+						warg.NewSubCmd(
+							"migrate",
+							"Migrate the database",
+							warg.Unimplemented(),
+							warg.NewCmdFlag(
+								"--db-url",
+								"Database URL",
+								scalar.String(),
+								warg.EnvVars("DATABASE_URL"),
+							),
+						),
+					),
+				),
+				warg.NewGlobalFlag(
+					"--verbose",
+					"Print more",
+					scalar.Bool(),
+				),
+				warg.EnvPrefix("MYAPP"),
+				warg.SkipAll(),
+			)
+			warg.GoldenTest(
+				t,
+				warg.GoldenTestArgs{
+					App:             &app,
+					UpdateGolden:    updateGolden,
+					ExpectActionErr: false,
+					Args:            tt.args,
+				},
+				warg.ParseWithLookupEnv(warg.LookupMap(nil)),
+			)
+		})
+	}
+}
+
+func TestFlagConstraintHelp(t *testing.T) {
+	updateGolden := os.Getenv("WARG_TEST_UPDATE_GOLDEN") != ""
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{
+			name: "detailedCommand",
+			args: []string{"list", "--help", "detailed"},
+		},
+		{
+			name: "compactCommand",
+			args: []string{"list", "--help", "compact"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := warg.New(
+				"myapp",
+				"v1.0.0",
+				warg.NewSection(
+					"Manage my app",
+					warg.NewSubCmd(
+						"list",
+						"List things",
+						warg.Unimplemented(),
+						warg.NewCmdFlag("--json", "Print JSON", scalar.Bool(), warg.FlagGroup("Output")),
+						warg.NewCmdFlag("--table", "Print a table", scalar.Bool(), warg.FlagGroup("Output")),
+						warg.NewCmdFlag("--user", "Username", scalar.String(), warg.FlagGroup("Auth")),
+						warg.NewCmdFlag("--password", "Password", scalar.String(), warg.FlagGroup("Auth")),
+						warg.MutuallyExclusive("--json", "--table"),
+						warg.RequiredTogether("--user", "--password"),
+						warg.DependsOn("--table", "--user"),
+					),
+				),
+				warg.SkipAll(),
+			)
+			warg.GoldenTest(
+				t,
+				warg.GoldenTestArgs{
+					App:             &app,
+					UpdateGolden:    updateGolden,
+					ExpectActionErr: false,
+					Args:            tt.args,
+				},
+				warg.ParseWithLookupEnv(warg.LookupMap(nil)),
+			)
+		})
+	}
+}
+
+func TestValueConstraintHelp(t *testing.T) {
+	updateGolden := os.Getenv("WARG_TEST_UPDATE_GOLDEN") != ""
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{
+			name: "detailedCommand",
+			args: []string{"serve", "--help", "detailed"},
+		},
+		{
+			name: "compactCommand",
+			args: []string{"serve", "--help", "compact"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := warg.New(
+				"myapp",
+				"v1.0.0",
+				warg.NewSection(
+					"Manage my app",
+					warg.NewSubCmd(
+						"serve",
+						"Serve things",
+						warg.Unimplemented(),
+						warg.NewCmdFlag("--port", "Port to listen on", scalar.Int(scalar.Default(8080), scalar.Min(1), scalar.Max(65535))),
+						warg.NewCmdFlag("--name", "Server name", scalar.String(scalar.Regex(`^[a-z]+$`))),
+						warg.NewCmdFlag("--host", "Hosts to serve", slice.String(slice.MinLen[string](1), slice.Unique[string]())),
+						warg.NewCmdFlag("--label", "Labels", dict.String(dict.AllowedKeys[string]("env", "team"))),
+					),
+				),
+				warg.SkipAll(),
+			)
+			warg.GoldenTest(
+				t,
+				warg.GoldenTestArgs{
+					App:             &app,
+					UpdateGolden:    updateGolden,
+					ExpectActionErr: false,
+					Args:            tt.args,
+				},
+				warg.ParseWithLookupEnv(warg.LookupMap(nil)),
+			)
+		})
+	}
+}
+
+func deprecatedHiddenApp() warg.App {
+	noop := func(warg.CmdContext) error { return nil }
+	return warg.New(
+		"myapp",
+		"v1.0.0",
+		warg.NewSection(
+			"Manage my app",
+			warg.NewSubCmd(
+				"serve",
+				"Serve things",
+				noop,
+				warg.NewCmdFlag("--addr", "Address to listen on", scalar.String()),
+				warg.NewCmdFlag("--port", "Port to listen on", scalar.Int(), warg.FlagDeprecated("use --addr instead")),
+				warg.NewCmdFlag("--debug-internals", "Debug internals", scalar.Bool(), warg.FlagHidden()),
+			),
+			warg.NewSubCmd("start", "Start serving", noop, warg.CmdDeprecated("use serve instead", warg.RemovedIn("v2.0.0"))),
+			warg.NewSubCmd("secret", "Secret command", noop, warg.CmdHidden()),
+			warg.NewSubSection(
+				"legacy",
+				"Legacy commands",
+				warg.SectionDeprecated("these will be removed"),
+				warg.NewSubCmd("migrate", "Migrate old data", noop),
+			),
+			warg.NewSubSection(
+				"internal",
+				"Internal commands",
+				warg.SectionHidden(),
+				warg.NewSubCmd("dump", "Dump state", noop),
+			),
+		),
+		warg.SkipAll(),
+	)
+}
+
+func TestDeprecatedHiddenHelp(t *testing.T) {
+	updateGolden := os.Getenv("WARG_TEST_UPDATE_GOLDEN") != ""
+	tests := []struct {
+		name   string
+		args   []string
+		lookup map[string]string
+	}{
+		{name: "compactSection", args: []string{"--help", "compact"}, lookup: nil},
+		{name: "detailedSection", args: []string{"--help", "detailed"}, lookup: nil},
+		{name: "compactCommand", args: []string{"serve", "--help", "compact"}, lookup: nil},
+		{name: "detailedCommand", args: []string{"serve", "--help", "detailed"}, lookup: nil},
+		{name: "detailedDeprecatedCommand", args: []string{"start", "--help", "detailed"}, lookup: nil},
+		{name: "outline", args: []string{"--help", "outline"}, lookup: nil},
+		{name: "allcommands", args: []string{"--help", "allcommands"}, lookup: nil},
+		{name: "hiddenStillRuns", args: []string{"internal", "dump"}, lookup: nil},
+		{name: "warnCommand", args: []string{"start"}, lookup: nil},
+		{name: "warnSection", args: []string{"legacy", "migrate"}, lookup: nil},
+		{name: "warnFlag", args: []string{"serve", "--port", "80"}, lookup: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := deprecatedHiddenApp()
+			warg.GoldenTest(
+				t,
+				warg.GoldenTestArgs{
+					App:             &app,
+					UpdateGolden:    updateGolden,
+					ExpectActionErr: false,
+					Args:            tt.args,
+				},
+				warg.ParseWithLookupEnv(warg.LookupMap(tt.lookup)),
+			)
+		})
+	}
+}
+
+func TestAliasHelp(t *testing.T) {
+	updateGolden := os.Getenv("WARG_TEST_UPDATE_GOLDEN") != ""
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "compactSection", args: []string{"--help", "compact"}},
+		{name: "detailedSection", args: []string{"--help", "detailed"}},
+		{name: "compactCommand", args: []string{"rm", "--help", "compact"}},
+		{name: "detailedCommand", args: []string{"rm", "--help", "detailed"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := warg.New(
+				"myapp",
+				"v1.0.0",
+				warg.NewSection(
+					"Manage my app",
+					warg.NewSubCmd(
+						"remove",
+						"Remove things",
+						warg.Unimplemented(),
+						warg.CmdAliases("rm"),
+						warg.NewCmdFlag("--force", "Don't ask", scalar.Bool(), warg.Alias("-f"), warg.Aliases("-y", "--yes")),
+						warg.NewCmdFlag("--database", "Database URL", scalar.String(), warg.RenamedFrom("--db")),
+					),
+					warg.NewSubSection(
+						"database",
+						"Database commands",
+						warg.SectionAliases("db"),
+						warg.NewSubCmd("migrate", "Migrate the database", warg.Unimplemented()),
+					),
+				),
+				warg.SkipAll(),
+			)
+			warg.GoldenTest(
+				t,
+				warg.GoldenTestArgs{
+					App:             &app,
+					UpdateGolden:    updateGolden,
+					ExpectActionErr: false,
+					Args:            tt.args,
+				},
+				warg.ParseWithLookupEnv(warg.LookupMap(nil)),
+			)
+		})
+	}
+}
+
+func TestSectionFlagHelp(t *testing.T) {
+	updateGolden := os.Getenv("WARG_TEST_UPDATE_GOLDEN") != ""
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "compactCommand", args: []string{"k8s", "pods", "get", "--help", "compact"}},
+		{name: "detailedCommand", args: []string{"k8s", "pods", "get", "--help", "detailed"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := warg.New(
+				"myapp",
+				"v1.0.0",
+				warg.NewSection(
+					"Manage my app",
+					warg.NewSectionFlag("--context", "Context to use", scalar.String(scalar.Default("default"))),
+					warg.NewSubSection(
+						"k8s",
+						"Manage Kubernetes",
+						warg.NewSectionFlag("--cluster", "Cluster to use", scalar.String(), warg.Required()),
+						warg.NewSubSection(
+							"pods",
+							"Manage pods",
+							warg.NewSectionFlag("--namespace", "Namespace to use", scalar.String(), warg.Alias("-n")),
+							warg.NewSubCmd(
+								"get",
+								"Get pods",
+								warg.Unimplemented(),
+								warg.NewCmdFlag("--watch", "Watch for changes", scalar.Bool()),
+							),
+						),
+					),
+				),
+				warg.EnvPrefix("MYAPP"),
+				warg.SkipAll(),
+			)
+			warg.GoldenTest(
+				t,
+				warg.GoldenTestArgs{
+					App:             &app,
+					UpdateGolden:    updateGolden,
+					ExpectActionErr: false,
+					Args:            tt.args,
+				},
+				warg.ParseWithLookupEnv(warg.LookupMap(nil)),
+			)
+		})
+	}
+}
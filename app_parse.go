@@ -1,13 +1,19 @@
 package warg
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"slices"
 	"sort"
+	"strconv"
+	"strings"
 
 	"go.bbkane.com/warg/colerr"
 	"go.bbkane.com/warg/config"
+	"go.bbkane.com/warg/dotenv"
 	"go.bbkane.com/warg/metadata"
 	"go.bbkane.com/warg/path"
 	"go.bbkane.com/warg/set"
@@ -21,6 +27,9 @@ import (
 type ParseOpts struct {
 	// Args []string
 
+	// Context is the parent of [CmdContext].Context. Defaults to [context.Background].
+	Context context.Context
+
 	// ParseMetadata for unstructured data. Useful for setting up mocks for tests (i.e., pass in in memory database and use it if it's here in the context)
 	ParseMetadata metadata.Metadata
 
@@ -48,10 +57,11 @@ type ParseOpts struct {
 // ParseOpt is a functional option for configuring [ParseOpts].
 type ParseOpt func(*ParseOpts)
 
-// NewParseOpts creates a [ParseOpts] with sensible defaults (os.Stderr, os.Stdin, os.Stdout,
+// NewParseOpts creates a [ParseOpts] with sensible defaults (context.Background, os.Stderr, os.Stdin, os.Stdout,
 // os.LookupEnv, empty metadata) and applies the given options.
 func NewParseOpts(opts ...ParseOpt) ParseOpts {
 	parseOptHolder := ParseOpts{
+		Context:       context.Background(),
 		ParseMetadata: metadata.Empty(),
 		LookupEnv:     os.LookupEnv,
 		Stderr:        os.Stderr,
@@ -116,13 +126,19 @@ const (
 //
 //   - [ParseArgState_WantSectionOrCmd]: only CurrentSection and SectionPath are valid.
 //   - [ParseArgState_WantFlagNameOrEnd], [ParseArgState_WantFlagValue]: all fields are valid.
+//
+// In [ParseArgState_WantFlagNameOrEnd], an arg that does not start with "-" (or is exactly "-") fills the next
+// positional argument (see [ParseState.NextPositional]) if the current command has one. To pass positionals
+// that start with "-", such as negative numbers, put them after "--". Commands with [AllowForwardedArgs]
+// forward every arg after "--" instead, so their positionals can't start with "-".
 type ParseState struct {
 	ParseArgState ParseArgState
 
 	SectionPath    []string
 	CurrentSection *Section
 
-	CurrentCmdName          string
+	CurrentCmdName string
+	// CurrentCmd is a copy of the current command. Its Flags also hold the flags inherited from its sections (see [NewSectionFlag]).
 	CurrentCmd              *Cmd
 	CurrentCmdForwardedArgs []string
 
@@ -133,9 +149,93 @@ type ParseState struct {
 	FlagValues     ValueMap
 	UnsetFlagNames set.Set[string]
 
+	// PositionalValues holds the current command's positional values, keyed by positional name. It is filled with empty values when a command is selected.
+	PositionalValues ValueMap
+	// PositionalIndex is the index into CurrentCmd.Positionals of the next positional to fill. Variadic positionals never advance it.
+	PositionalIndex int
+
+	// FlagSources records where each set flag's value came from, keyed by flag name. Flags passed on the
+	// command line are recorded while parsing args, and the rest are recorded when flags are resolved.
+	FlagSources map[string]FlagSource
+	// PositionalSources records the args that filled each passed positional, keyed by positional name.
+	// Variadic positionals can be filled by several args.
+	PositionalSources map[string]FlagSource
+
 	HelpPassed bool
 }
 
+// NextPositional returns the positional the next non-flag arg will fill, or nil if
+// no command is selected or all positionals are filled.
+func (ps *ParseState) NextPositional() *Positional {
+	if ps.CurrentCmd == nil || ps.PositionalIndex >= len(ps.CurrentCmd.Positionals) {
+		return nil
+	}
+	return &ps.CurrentCmd.Positionals[ps.PositionalIndex]
+}
+
+// cmdPath returns the section path and current command name, without the app name.
+func (ps *ParseState) cmdPath() []string {
+	ret := slices.Clone(ps.SectionPath)
+	if ps.CurrentCmdName != "" {
+		ret = append(ret, ps.CurrentCmdName)
+	}
+	return ret
+}
+
+// FlagSource records where a flag's value came from. Only the fields relevant to UpdatedBy are set.
+type FlagSource struct {
+	UpdatedBy value.UpdatedBy
+
+	// ArgIndexes are the indexes (into the args passed to [App.Parse]) of the args holding the flag's values.
+	// Slice flags can be passed multiple times.
+	ArgIndexes []int
+
+	// EnvVar is the name of the env var that supplied the value.
+	EnvVar string
+	// DotEnvFilePath is the dotenv file that supplied the env var, or "" if it came from the environment.
+	DotEnvFilePath string
+
+	// ConfigFilePath is the config file that supplied the value.
+	ConfigFilePath string
+	// ConfigPath is the path searched for in the config file (see [ConfigPath]).
+	ConfigPath string
+}
+
+func newPassedFlagSource(argIndex int) FlagSource {
+	return FlagSource{
+		UpdatedBy:      value.UpdatedByFlag,
+		ArgIndexes:     []int{argIndex},
+		EnvVar:         "",
+		DotEnvFilePath: "",
+		ConfigFilePath: "",
+		ConfigPath:     "",
+	}
+}
+
+// Origin describes where the value came from in more detail than UpdatedBy, such as
+// "args[2]", "MYAPP_KEY", or "config.yaml: key". It is empty for default and unset values.
+func (fs FlagSource) Origin() string {
+	switch fs.UpdatedBy {
+	case value.UpdatedByFlag, value.UpdatedByPositional:
+		indexes := make([]string, 0, len(fs.ArgIndexes))
+		for _, i := range fs.ArgIndexes {
+			indexes = append(indexes, "args["+strconv.Itoa(i)+"]")
+		}
+		return strings.Join(indexes, ", ")
+	case value.UpdatedByEnvVar:
+		if fs.DotEnvFilePath != "" {
+			return fs.EnvVar + " (" + fs.DotEnvFilePath + ")"
+		}
+		return fs.EnvVar
+	case value.UpdatedByConfig:
+		return fs.ConfigFilePath + ": " + fs.ConfigPath
+	case value.UpdatedByDefault, value.UpdatedByUnset:
+		return ""
+	default:
+		return ""
+	}
+}
+
 // parseArgs parses the args into a ParseState. It does not resolve flag values from config/env/defaults, only from the command line, so call resolveFlags afterwards to get a resolved ParseState.
 func (app *App) parseArgs(args []string) (ParseState, error) {
 	pr := ParseState{
@@ -153,13 +253,19 @@ func (app *App) parseArgs(args []string) (ParseState, error) {
 		FlagValues:      make(ValueMap),
 		UnsetFlagNames:  set.New[string](),
 
+		PositionalValues: make(ValueMap),
+		PositionalIndex:  0,
+
+		FlagSources:       make(map[string]FlagSource),
+		PositionalSources: make(map[string]FlagSource),
+
 		HelpPassed: false,
 	}
 
 	aliasToFlagName := make(map[string]string)
 	for flagName, fl := range app.GlobalFlags {
-		if fl.Alias != "" {
-			aliasToFlagName[string(fl.Alias)] = flagName
+		for _, alias := range fl.allAliases() {
+			aliasToFlagName[alias] = flagName
 		}
 	}
 
@@ -171,10 +277,26 @@ func (app *App) parseArgs(args []string) (ParseState, error) {
 
 	for i, arg := range args {
 
+		// --help=<helptype> must be the last thing passed and can appear at any state we aren't expecting a flag value
+		if name, helpType, found := strings.Cut(arg, "="); found &&
+			i == len(args)-1 &&
+			name != "" &&
+			app.GlobalFlags[app.HelpFlagName].hasName(app.HelpFlagName, name) &&
+			pr.ParseArgState != ParseArgState_WantFlagValue {
+
+			pr.HelpPassed = true
+			err := pr.FlagValues[app.HelpFlagName].Update(helpType, value.UpdatedByFlag)
+			if err != nil {
+				return pr, colerr.NewWrapped(err, "Error updating help flag")
+			}
+			pr.FlagSources[app.HelpFlagName] = newPassedFlagSource(i)
+			return pr, nil
+		}
+
 		// --help <helptype> or --help must be the last thing passed and can appear at any state we aren't expecting a flag value
 		if i >= len(args)-2 &&
 			arg != "" && // just in case there's not help flag alias
-			(arg == app.HelpFlagName || arg == app.GlobalFlags[app.HelpFlagName].Alias) &&
+			app.GlobalFlags[app.HelpFlagName].hasName(app.HelpFlagName, arg) &&
 			pr.ParseArgState != ParseArgState_WantFlagValue {
 
 			pr.HelpPassed = true
@@ -184,6 +306,7 @@ func (app *App) parseArgs(args []string) (ParseState, error) {
 				if err != nil {
 					return pr, colerr.NewWrapped(err, "Error updating help flag")
 				}
+				pr.FlagSources[app.HelpFlagName] = newPassedFlagSource(i + 1)
 			}
 
 			return pr, nil
@@ -191,12 +314,19 @@ func (app *App) parseArgs(args []string) (ParseState, error) {
 
 		switch pr.ParseArgState {
 		case ParseArgState_WantSectionOrCmd:
-			if childSection, exists := pr.CurrentSection.Sections[string(arg)]; exists {
+			if app.AllowPrefixMatching {
+				if name, found := pr.CurrentSection.lookupPrefix(arg); found {
+					arg = name
+				}
+			}
+			if sectionName, exists := pr.CurrentSection.Sections.lookupName(arg); exists {
+				childSection := pr.CurrentSection.Sections[sectionName]
 				pr.CurrentSection = &childSection
-				pr.SectionPath = append(pr.SectionPath, arg)
-			} else if childCommand, exists := pr.CurrentSection.Cmds[string(arg)]; exists {
+				pr.SectionPath = append(pr.SectionPath, sectionName)
+			} else if cmdName, exists := pr.CurrentSection.Cmds.lookupName(arg); exists {
+				childCommand := app.inheritFlags(pr.SectionPath, pr.CurrentSection.Cmds[cmdName])
 				pr.CurrentCmd = &childCommand
-				pr.CurrentCmdName = string(arg)
+				pr.CurrentCmdName = cmdName
 
 				// fill the FlagValues map with empty values from the command
 				// All names in (command flag names, command flag aliases, global flag names, global flag aliases)
@@ -204,16 +334,19 @@ func (app *App) parseArgs(args []string) (ParseState, error) {
 				for flagName, f := range pr.CurrentCmd.Flags {
 					pr.FlagValues[flagName] = f.EmptyValueConstructor()
 
-					if f.Alias != "" {
-						aliasToFlagName[string(f.Alias)] = flagName
+					for _, alias := range f.allAliases() {
+						aliasToFlagName[alias] = flagName
 					}
 
 				}
+				for _, p := range pr.CurrentCmd.Positionals {
+					pr.PositionalValues[p.Name] = p.EmptyValueConstructor()
+				}
 				pr.ParseArgState = ParseArgState_WantFlagNameOrEnd
 			} else {
 				choices := make([]string, 0, len(pr.CurrentSection.Sections)+len(pr.CurrentSection.Cmds))
-				choices = append(choices, pr.CurrentSection.Sections.SortedNames()...)
-				choices = append(choices, pr.CurrentSection.Cmds.SortedNames()...)
+				choices = append(choices, pr.CurrentSection.Sections.visibleSortedNames()...)
+				choices = append(choices, pr.CurrentSection.Cmds.visibleSortedNames()...)
 				return pr, colerr.ArgChoiceError{
 					Message: "expecting section or command",
 					Arg:     arg,
@@ -234,45 +367,99 @@ func (app *App) parseArgs(args []string) (ParseState, error) {
 				return pr, nil
 			}
 
+			// without forwarded args, "--" ends flags: every remaining arg fills positionals, even "-5" or "--name"
+			if flagName == "--" && len(pr.CurrentCmd.Positionals) > 0 {
+				for j, posArg := range args[i+1:] {
+					if pr.NextPositional() == nil {
+						return pr, colerr.NewWrappedf(nil, "Unexpected positional argument after --: %s", posArg)
+					}
+					err := pr.updateNextPositional(posArg, i+1+j)
+					if err != nil {
+						return pr, err
+					}
+				}
+				return pr, nil
+			}
+
+			// anything that doesn't look like a flag fills the next positional. "-" conventionally means stdin.
+			if pr.NextPositional() != nil && (arg == "-" || !strings.HasPrefix(arg, "-")) {
+				err := pr.updateNextPositional(arg, i)
+				if err != nil {
+					return pr, err
+				}
+				continue
+			}
+
+			// --flag=value or -f=value
+			flagName, flagValue, hasFlagValue := strings.Cut(arg, "=")
+
+			// bundled single-dash aliases: -abc or -n3
+			if !hasFlagValue && isBundledAliases(arg, aliasToFlagName, app.GlobalFlags, pr.CurrentCmd.Flags) {
+				err := pr.parseBundledAliases(arg, i, aliasToFlagName, app.GlobalFlags)
+				if err != nil {
+					return pr, err
+				}
+				continue
+			}
+
 			if actualFlagName, exists := aliasToFlagName[flagName]; exists {
 				flagName = actualFlagName
 			}
 			fl := findFlag(flagName, app.GlobalFlags, pr.CurrentCmd.Flags)
-			if fl == nil {
-				choices := make([]string, 0, len(app.GlobalFlags)+len(pr.CurrentCmd.Flags))
-				choices = append(choices, app.GlobalFlags.SortedNames()...)
-				choices = append(choices, pr.CurrentCmd.Flags.SortedNames()...)
-				// Also include aliases
-				aliases := make([]string, 0)
-				for alias := range aliasToFlagName {
-					aliases = append(aliases, alias)
+
+			// a prefix can resolve to a negated name, which is handled below
+			if fl == nil && app.AllowPrefixMatching {
+				if name, found := lookupFlagPrefix(flagName, app.GlobalFlags, pr.CurrentCmd.Flags); found {
+					flagName = name
+					fl = findFlag(flagName, app.GlobalFlags, pr.CurrentCmd.Flags)
+				}
+			}
+
+			// --no-<name> for negatable flags
+			negated := false
+			if fl == nil && !hasFlagValue && strings.HasPrefix(flagName, "--no-") {
+				positiveName := "--" + strings.TrimPrefix(flagName, "--no-")
+				if positiveFl := findFlag(positiveName, app.GlobalFlags, pr.CurrentCmd.Flags); positiveFl != nil && positiveFl.Negatable {
+					flagName = positiveName
+					fl = positiveFl
+					negated = true
 				}
-				sort.Strings(aliases)
-				choices = append(choices, aliases...)
+			}
+
+			if fl == nil {
 				return pr, colerr.ArgChoiceError{
 					Message: "expecting flag name",
 					Arg:     arg,
-					Choices: choices,
+					Choices: pr.flagNameChoices(app.GlobalFlags),
 				}
 			}
 			pr.CurrentFlagName = flagName
 			pr.CurrentFlag = fl
 			pr.ParseArgState = ParseArgState_WantFlagValue
 
-		case ParseArgState_WantFlagValue:
-			// if the flag has an unset sentinel and the user passed it, unset the flag
-			// NOTE: UnsetSentinel must be a pointer to a string, because sometimes the user may pass an empty string
-			if pr.CurrentFlag.UnsetSentinel != nil && arg == *pr.CurrentFlag.UnsetSentinel {
-				pr.FlagValues[pr.CurrentFlagName] = pr.CurrentFlag.EmptyValueConstructor()
-				pr.UnsetFlagNames.Add(pr.CurrentFlagName)
-			} else {
-				err := pr.FlagValues[pr.CurrentFlagName].Update(arg, value.UpdatedByFlag)
+			switch {
+			case hasFlagValue:
+				err := pr.updateCurrentFlag(flagValue, i)
 				if err != nil {
-					return pr, colerr.NewWrappedf(err, "Error updating flag %v with value %v", pr.CurrentFlagName, arg)
+					return pr, err
+				}
+			case negated:
+				err := pr.updateCurrentFlag("false", i)
+				if err != nil {
+					return pr, err
+				}
+			case fl.Switch:
+				err := pr.updateCurrentFlag("true", i)
+				if err != nil {
+					return pr, err
 				}
-				pr.UnsetFlagNames.Delete(pr.CurrentFlagName)
 			}
-			pr.ParseArgState = ParseArgState_WantFlagNameOrEnd
+
+		case ParseArgState_WantFlagValue:
+			err := pr.updateCurrentFlag(arg, i)
+			if err != nil {
+				return pr, err
+			}
 
 		default:
 			panic("unexpected state: " + pr.ParseArgState)
@@ -281,6 +468,149 @@ func (app *App) parseArgs(args []string) (ParseState, error) {
 	return pr, nil
 }
 
+// updateNextPositional updates the positional from [ParseState.NextPositional] with arg, records its
+// source, and advances PositionalIndex past non-variadic positionals. argIndex is the index of arg.
+func (pr *ParseState) updateNextPositional(arg string, argIndex int) error {
+	pos := pr.NextPositional()
+	err := pr.PositionalValues[pos.Name].Update(arg, value.UpdatedByPositional)
+	if err != nil {
+		return colerr.NewWrappedf(err, "Error updating positional %s with value %s", pos.Name, arg)
+	}
+	source, exists := pr.PositionalSources[pos.Name]
+	if !exists {
+		source = newPassedFlagSource(argIndex)
+		source.UpdatedBy = value.UpdatedByPositional
+	} else {
+		source.ArgIndexes = append(source.ArgIndexes, argIndex)
+	}
+	pr.PositionalSources[pos.Name] = source
+	if !pos.Variadic {
+		pr.PositionalIndex++
+	}
+	return nil
+}
+
+// updateCurrentFlag updates CurrentFlag with a passed value (or unsets it if the value is its UnsetSentinel)
+// and moves the parser back to [ParseArgState_WantFlagNameOrEnd]. argIndex is the index of the arg holding the value.
+func (pr *ParseState) updateCurrentFlag(arg string, argIndex int) error {
+	// if the flag has an unset sentinel and the user passed it, unset the flag
+	// NOTE: UnsetSentinel must be a pointer to a string, because sometimes the user may pass an empty string
+	if pr.CurrentFlag.UnsetSentinel != nil && arg == *pr.CurrentFlag.UnsetSentinel {
+		pr.FlagValues[pr.CurrentFlagName] = pr.CurrentFlag.EmptyValueConstructor()
+		pr.UnsetFlagNames.Add(pr.CurrentFlagName)
+		delete(pr.FlagSources, pr.CurrentFlagName)
+	} else {
+		err := pr.FlagValues[pr.CurrentFlagName].Update(arg, value.UpdatedByFlag)
+		if err != nil {
+			return colerr.NewWrappedf(err, "Error updating flag %v with value %v", pr.CurrentFlagName, arg)
+		}
+		pr.UnsetFlagNames.Delete(pr.CurrentFlagName)
+		source := pr.FlagSources[pr.CurrentFlagName]
+		source.UpdatedBy = value.UpdatedByFlag
+		source.ArgIndexes = append(source.ArgIndexes, argIndex)
+		pr.FlagSources[pr.CurrentFlagName] = source
+	}
+	pr.ParseArgState = ParseArgState_WantFlagNameOrEnd
+	return nil
+}
+
+// flagNameChoices lists the flag names, aliases, and next positional valid in the current parse state, for error messages.
+// Hidden flags and names flags were renamed from are left out.
+func (pr *ParseState) flagNameChoices(globalFlags FlagMap) []string {
+	choices := make([]string, 0, len(globalFlags)+len(pr.CurrentCmd.Flags))
+	choices = append(choices, globalFlags.visibleSortedNames()...)
+	choices = append(choices, pr.CurrentCmd.Flags.visibleSortedNames()...)
+	// Also include aliases
+	aliases := make([]string, 0)
+	for _, fm := range []FlagMap{globalFlags, pr.CurrentCmd.Flags} {
+		for _, fl := range fm {
+			if !fl.Hidden {
+				aliases = append(aliases, fl.helpAliases()...)
+			}
+		}
+	}
+	sort.Strings(aliases)
+	choices = append(choices, aliases...)
+	// Also include negations
+	negations := make([]string, 0)
+	for _, fm := range []FlagMap{globalFlags, pr.CurrentCmd.Flags} {
+		for name, fl := range fm {
+			if fl.Negatable && !fl.Hidden {
+				negations = append(negations, negatedName(name))
+			}
+		}
+	}
+	sort.Strings(negations)
+	choices = append(choices, negations...)
+	if pos := pr.NextPositional(); pos != nil {
+		choices = append(choices, pos.Name)
+	}
+	return choices
+}
+
+// isBundledAliases reports whether arg looks like several single-character aliases passed together (-abc)
+// or a single-character alias with its value attached (-n3), rather than an existing flag name or alias.
+func isBundledAliases(arg string, aliasToFlagName map[string]string, globalFlags FlagMap, currentCommandFlags FlagMap) bool {
+	if len(arg) < 3 || arg[0] != '-' || arg[1] == '-' {
+		return false
+	}
+	if _, exists := aliasToFlagName[arg]; exists {
+		return false
+	}
+	if findFlag(arg, globalFlags, currentCommandFlags) != nil {
+		return false
+	}
+	// the first character must be an alias for this to be a bundle
+	_, exists := aliasToFlagName[arg[:2]]
+	return exists
+}
+
+// parseBundledAliases parses POSIX-style bundled aliases. Each [Switch] alias is set to true. The first
+// other alias takes the rest of arg as its value (-n3), or the next arg if nothing is left (-vn 3). This
+// includes bool aliases that aren't switches, so they need a value in a bundle just like when passed alone.
+func (pr *ParseState) parseBundledAliases(arg string, argIndex int, aliasToFlagName map[string]string, globalFlags FlagMap) error {
+	for i := 1; i < len(arg); i++ {
+		alias := "-" + arg[i:i+1]
+		flagName, exists := aliasToFlagName[alias]
+		if !exists {
+			return colerr.ArgChoiceError{
+				Message: "expecting flag alias in " + arg,
+				Arg:     alias,
+				Choices: pr.flagNameChoices(globalFlags),
+			}
+		}
+		fl := findFlag(flagName, globalFlags, pr.CurrentCmd.Flags)
+		pr.CurrentFlagName = flagName
+		pr.CurrentFlag = fl
+		pr.ParseArgState = ParseArgState_WantFlagValue
+
+		if fl.Switch {
+			err := pr.updateCurrentFlag("true", argIndex)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if rest := arg[i+1:]; rest != "" {
+			return pr.updateCurrentFlag(rest, argIndex)
+		}
+		// wait for the next arg to be the value
+		return nil
+	}
+	return nil
+}
+
+// isBoolValue reports whether v holds a bool or a []bool.
+func isBoolValue(v value.Value) bool {
+	switch v.Get().(type) {
+	case bool, []bool:
+		return true
+	default:
+		return false
+	}
+}
+
 func findFlag(flagName string, globalFlags FlagMap, currentCommandFlags FlagMap) *Flag {
 	if fl, exists := globalFlags[flagName]; exists {
 		return &fl
@@ -291,13 +621,61 @@ func findFlag(flagName string, globalFlags FlagMap, currentCommandFlags FlagMap)
 	return nil
 }
 
+// envSourceLookup looks up an env var, returning its value, the dotenv file it came from
+// ("" if it came from the environment), and whether it exists.
+type envSourceLookup func(key string) (string, string, bool)
+
+// dotEnvLookup layers the app's dotenv files under lookupEnv. The environment takes precedence over
+// dotenv files, and later dotenv files take precedence over earlier ones. Missing dotenv files are skipped.
+func (app *App) dotEnvLookup(lookupEnv LookupEnv) (envSourceLookup, error) {
+	type dotEnvValue struct {
+		val      string
+		filePath string
+	}
+	dotEnvValues := make(map[string]dotEnvValue)
+
+	for _, filePath := range app.DotEnvFiles {
+		expanded, err := path.New(filePath).Expand()
+		if err != nil {
+			return nil, colerr.NewWrappedf(err, "Error expanding dotenv path ( %s ) ", filePath)
+		}
+		// ${VAR} in dotenv files can reference the environment and earlier dotenv files
+		earlier := make(map[string]string, len(dotEnvValues))
+		for k, dv := range dotEnvValues {
+			earlier[k] = dv.val
+		}
+		vars, err := dotenv.ReadFile(expanded, dotenv.LookupEnv(lookupEnv), earlier)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, colerr.NewWrappedf(err, "Error reading dotenv file ( %s ) ", filePath)
+		}
+		for k, v := range vars {
+			dotEnvValues[k] = dotEnvValue{val: v, filePath: filePath}
+		}
+	}
+
+	return func(key string) (string, string, bool) {
+		if val, exists := lookupEnv(key); exists {
+			return val, "", true
+		}
+		if dv, exists := dotEnvValues[key]; exists {
+			return dv.val, dv.filePath, true
+		}
+		return "", "", false
+	}, nil
+}
+
 func resolveFlag(
 	flagName string,
 	fl Flag,
 	flagValues ValueMap, // this gets updated - all other params are readonly
 	configReader config.Reader,
-	lookupEnv LookupEnv,
+	envVars []string,
+	lookupEnv envSourceLookup,
 	unsetFlagNames set.Set[string],
+	flagSources map[string]FlagSource, // this gets updated with where the value came from
 ) error {
 
 	// don't update if its been explicitly unset or already set
@@ -305,9 +683,16 @@ func resolveFlag(
 		return nil
 	}
 
-	// config
-	if fl.ConfigPath != "" && configReader != nil {
-		fpr, err := configReader.Search(fl.ConfigPath)
+	// config - search the current path first, then any paths the flag was renamed from
+	configPaths := []string{fl.ConfigPath}
+	for _, fr := range fl.RenamedFrom {
+		configPaths = append(configPaths, fr.ConfigPath)
+	}
+	for _, configPath := range configPaths {
+		if configPath == "" || configReader == nil {
+			continue
+		}
+		fpr, err := configReader.Search(configPath)
 		if err != nil {
 			return err
 		}
@@ -321,19 +706,35 @@ func resolveFlag(
 					fmt.Sprintf("%#v", fpr.IFace),
 				)
 			}
+			flagSources[flagName] = FlagSource{
+				UpdatedBy:      value.UpdatedByConfig,
+				ArgIndexes:     nil,
+				EnvVar:         "",
+				DotEnvFilePath: "",
+				ConfigFilePath: fpr.FilePath,
+				ConfigPath:     configPath,
+			}
 			return nil
 
 		}
 	}
 
 	// envvar
-	for _, e := range fl.EnvVars {
-		val, exists := lookupEnv(e)
+	for _, e := range envVars {
+		val, dotEnvFilePath, exists := lookupEnv(e)
 		if exists {
 			err := flagValues[flagName].Update(val, value.UpdatedByEnvVar)
 			if err != nil {
 				return colerr.NewWrappedf(err, "Error updating flag %s from envvar %s", fmt.Sprintf("%v", flagName), fmt.Sprintf("%v", val))
 			}
+			flagSources[flagName] = FlagSource{
+				UpdatedBy:      value.UpdatedByEnvVar,
+				ArgIndexes:     nil,
+				EnvVar:         e,
+				DotEnvFilePath: dotEnvFilePath,
+				ConfigFilePath: "",
+				ConfigPath:     "",
+			}
 			// Use first env var found
 			return nil
 		}
@@ -345,47 +746,89 @@ func resolveFlag(
 		if err != nil {
 			return colerr.NewWrappedf(err, "Error updating flag %s from default", fmt.Sprintf("%v", flagName))
 		}
+		flagSources[flagName] = FlagSource{
+			UpdatedBy:      value.UpdatedByDefault,
+			ArgIndexes:     nil,
+			EnvVar:         "",
+			DotEnvFilePath: "",
+			ConfigFilePath: "",
+			ConfigPath:     "",
+		}
 		return nil
 	}
 	return nil
 }
 
-// resolveFlags resolves the config flag first, and then uses its values to resolve the rest of the flags.
-func (app *App) resolveFlags(currentCmd *Cmd, flagValues ValueMap, lookupEnv LookupEnv, unsetFlagNames set.Set[string]) error {
+// resolveFlags resolves the config flag first, and then uses its values (layered over any [ConfigFile] sources) to resolve the rest of the flags.
+// Config files aren't read for commands with [CmdSkipConfig].
+func (app *App) resolveFlags(pr *ParseState, osLookupEnv LookupEnv) error {
+	lookupEnv, err := app.dotEnvLookup(osLookupEnv)
+	if err != nil {
+		return err
+	}
+
+	flagValues := pr.FlagValues
+	unsetFlagNames := pr.UnsetFlagNames
+	flagSources := pr.FlagSources
+
+	// config files are searched in reverse order, so add the lowest precedence ones first
+	configReaders := []config.Reader{}
+	configFiles := app.ConfigFiles
+	skipConfig := pr.CurrentCmd != nil && pr.CurrentCmd.SkipConfig
+	if skipConfig {
+		configFiles = nil
+	}
+	for _, cf := range configFiles {
+		configPathStr, err := path.New(cf.FilePath).Expand()
+		if err != nil {
+			return colerr.NewWrappedf(err, "Error expanding config path ( %s ) ", cf.FilePath)
+		}
+		reader, err := cf.NewReader(configPathStr)
+		if err != nil {
+			return colerr.NewWrappedf(err, "Error reading config path ( %s ) ", cf.FilePath)
+		}
+		configReaders = append(configReaders, reader)
+	}
+
 	// resolve config flag first and try to get a reader
-	var configReader config.Reader
 	if app.ConfigFlagName != "" {
 		err := resolveFlag(
-			app.ConfigFlagName, app.GlobalFlags[app.ConfigFlagName], flagValues, nil, lookupEnv, unsetFlagNames)
+			app.ConfigFlagName, app.GlobalFlags[app.ConfigFlagName], flagValues, nil,
+			app.flagEnvVars(app.ConfigFlagName, app.GlobalFlags[app.ConfigFlagName], nil), lookupEnv, unsetFlagNames, flagSources)
 		if err != nil {
 			return colerr.NewWrappedf(err, "ResolveFlag error for flag %s", app.ConfigFlagName)
 		}
-		if flagValues[app.ConfigFlagName].UpdatedBy() != value.UpdatedByUnset {
+		if flagValues[app.ConfigFlagName].UpdatedBy() != value.UpdatedByUnset && !skipConfig {
 			configPath := flagValues[app.ConfigFlagName].Get().(path.Path)
 			configPathStr, err := configPath.Expand()
 			if err != nil {
 				return colerr.NewWrappedf(err, "Error expanding config path ( %s ) ", configPath.String())
 			}
-			configReader, err = app.NewConfigReader(configPathStr)
+			reader, err := app.NewConfigReader(configPathStr)
 			if err != nil {
 				return colerr.NewWrappedf(err, "Error reading config path ( %s ) ", configPath.String())
 			}
-
+			configReaders = append(configReaders, reader)
 		}
 	}
 
+	var configReader config.Reader
+	if len(configReaders) > 0 {
+		configReader = config.NewLayeredReader(configReaders...)
+	}
+
 	// resolve app global flags
 	for flagName, fl := range app.GlobalFlags {
-		err := resolveFlag(flagName, fl, flagValues, configReader, lookupEnv, unsetFlagNames)
+		err := resolveFlag(flagName, fl, flagValues, configReader, app.flagEnvVars(flagName, fl, nil), lookupEnv, unsetFlagNames, flagSources)
 		if err != nil {
 			return colerr.NewWrappedf(err, "ResolveFlag error for global flag %s", flagName)
 		}
 	}
 
 	// resolve current command flags
-	if currentCmd != nil { // can be nil in the case of --help
-		for flagName, fl := range currentCmd.Flags {
-			err := resolveFlag(flagName, fl, flagValues, configReader, lookupEnv, unsetFlagNames)
+	if pr.CurrentCmd != nil { // can be nil in the case of --help
+		for flagName, fl := range pr.CurrentCmd.Flags {
+			err := resolveFlag(flagName, fl, flagValues, configReader, app.flagEnvVars(flagName, fl, app.cmdFlagEnvPath(pr, flagName)), lookupEnv, unsetFlagNames, flagSources)
 			if err != nil {
 				return colerr.NewWrappedf(err, "ResolveFlag error for command flag %s", flagName)
 			}
@@ -395,6 +838,24 @@ func (app *App) resolveFlags(currentCmd *Cmd, flagValues ValueMap, lookupEnv Loo
 	return nil
 }
 
+// resolvePositionals fills unset positionals from their defaults. Positionals are only passed
+// on the command line, so config files and environment variables are not consulted.
+func resolvePositionals(currentCmd *Cmd, positionalValues ValueMap) error {
+	if currentCmd == nil {
+		return nil
+	}
+	for _, pos := range currentCmd.Positionals {
+		val := positionalValues[pos.Name]
+		if val.UpdatedBy() == value.UpdatedByUnset && val.HasDefault() {
+			err := val.ReplaceFromDefault(value.UpdatedByDefault)
+			if err != nil {
+				return colerr.NewWrappedf(err, "Error updating positional %s from default", pos.Name)
+			}
+		}
+	}
+	return nil
+}
+
 // Parse parses the given args (formatted like os.Args, with the program name as the first element)
 // using command-line arguments, environment variables, and config files to produce a [ParseResult].
 // Returns an error if parsing fails or required flags are missing.
@@ -409,7 +870,11 @@ func (app *App) Parse(args []string, opts ...ParseOpt) (*ParseResult, error) {
 
 	// --help means we don't need to do a lot of error checking
 	if parseState.HelpPassed || parseState.ParseArgState == ParseArgState_WantSectionOrCmd {
-		err = app.resolveFlags(parseState.CurrentCmd, parseState.FlagValues, parseOpts.LookupEnv, parseState.UnsetFlagNames)
+		err = app.resolveFlags(&parseState, parseOpts.LookupEnv)
+		if err != nil {
+			return nil, err
+		}
+		err = resolvePositionals(parseState.CurrentCmd, parseState.PositionalValues)
 		if err != nil {
 			return nil, err
 		}
@@ -419,9 +884,11 @@ func (app *App) Parse(args []string, opts ...ParseOpt) (*ParseResult, error) {
 		pr := ParseResult{
 			Context: CmdContext{
 				App:           app,
+				Context:       parseOpts.Context,
 				ParseMetadata: parseOpts.ParseMetadata,
 				Flags:         parseState.FlagValues.ToPassedFlags(),
 				ForwardedArgs: parseState.CurrentCmdForwardedArgs,
+				Positionals:   parseState.PositionalValues.ToPassedFlags(),
 				ParseState:    &parseState,
 				Stderr:        parseOpts.Stderr,
 				Stdin:         parseOpts.Stdin,
@@ -437,7 +904,7 @@ func (app *App) Parse(args []string, opts ...ParseOpt) (*ParseResult, error) {
 		return nil, colerr.NewWrappedf(nil, "Unexpected parse state: %s", string(parseState.ParseArgState))
 	}
 
-	err = app.resolveFlags(parseState.CurrentCmd, parseState.FlagValues, parseOpts.LookupEnv, parseState.UnsetFlagNames)
+	err = app.resolveFlags(&parseState, parseOpts.LookupEnv)
 	if err != nil {
 		return nil, err
 	}
@@ -455,22 +922,54 @@ func (app *App) Parse(args []string, opts ...ParseOpt) (*ParseResult, error) {
 		}
 	}
 
+	// collect problems with the resolved values so they're all reported at once (see runValidators)
+	var errs []error
 	if len(missingRequiredFlags) > 0 {
-		return nil, colerr.NewWrappedf(nil, "Missing but required flags: %s", fmt.Sprintf("%s", missingRequiredFlags))
+		errs = append(errs, colerr.NewWrappedf(nil, "Missing but required flags: %s", fmt.Sprintf("%s", missingRequiredFlags)))
+	}
+
+	err = parseState.CurrentCmd.checkFlagConstraints(parseState.FlagValues, parseState.FlagSources)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	err = resolvePositionals(parseState.CurrentCmd, parseState.PositionalValues)
+	if err != nil {
+		return nil, err
+	}
+
+	missingRequiredPositionals := []string{}
+	for _, pos := range parseState.CurrentCmd.Positionals {
+		if pos.Required && !parseState.PositionalValues.IsSet(pos.Name) {
+			missingRequiredPositionals = append(missingRequiredPositionals, pos.Name)
+		}
+	}
+
+	if len(missingRequiredPositionals) > 0 {
+		errs = append(errs, colerr.NewWrappedf(nil, "Missing but required positional args: %s", fmt.Sprintf("%s", missingRequiredPositionals)))
 	}
 
 	pr := ParseResult{
 		Context: CmdContext{
 			App:           app,
+			Context:       parseOpts.Context,
 			ParseMetadata: parseOpts.ParseMetadata,
 			Flags:         parseState.FlagValues.ToPassedFlags(),
 			ForwardedArgs: parseState.CurrentCmdForwardedArgs,
+			Positionals:   parseState.PositionalValues.ToPassedFlags(),
 			ParseState:    &parseState,
 			Stderr:        parseOpts.Stderr,
 			Stdin:         parseOpts.Stdin,
 			Stdout:        parseOpts.Stdout,
 		},
-		Action: parseState.CurrentCmd.Action,
+		Action: app.wrappedAction(&parseState),
 	}
+
+	err = runValidators(pr.Context, errs)
+	if err != nil {
+		return nil, err
+	}
+
+	warnDeprecated(pr.Context)
 	return &pr, nil
 }
@@ -4,6 +4,8 @@ package warg_test
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"slices"
@@ -12,9 +14,11 @@ import (
 	"go.bbkane.com/warg"
 	"go.bbkane.com/warg/config"
 	"go.bbkane.com/warg/config/jsonreader"
+	"go.bbkane.com/warg/config/tomlreader"
 	"go.bbkane.com/warg/config/yamlreader"
 	"go.bbkane.com/warg/metadata"
 	"go.bbkane.com/warg/path"
+	"go.bbkane.com/warg/value"
 	"go.bbkane.com/warg/value/dict"
 	"go.bbkane.com/warg/value/scalar"
 	"go.bbkane.com/warg/value/slice"
@@ -661,6 +665,421 @@ func TestApp_Parse_unsetSetinel(t *testing.T) {
 	}
 }
 
+func TestApp_Parse_positionals(t *testing.T) {
+	//exhaustruct:ignore  // in tests I like to only set what I don't expect
+	tests := []struct {
+		name                      string
+		cmdOpts                   []warg.CmdOpt
+		args                      []string
+		expectedPassedFlagValues  warg.PassedFlags
+		expectedPassedPositionals warg.PassedFlags
+		expectedErr               bool
+	}{
+		{
+			name: "requiredPositionals",
+			cmdOpts: []warg.CmdOpt{
+				warg.CmdPositional("SRC", "source", scalar.String(), warg.PositionalRequired()),
+				warg.CmdPositional("DST", "destination", scalar.String(), warg.PositionalRequired()),
+			},
+			args:                      []string{"copy", "a.txt", "b.txt"},
+			expectedPassedFlagValues:  warg.PassedFlags{"--help": "default"},
+			expectedPassedPositionals: warg.PassedFlags{"SRC": "a.txt", "DST": "b.txt"},
+		},
+		{
+			name: "missingRequiredPositional",
+			cmdOpts: []warg.CmdOpt{
+				warg.CmdPositional("SRC", "source", scalar.String(), warg.PositionalRequired()),
+				warg.CmdPositional("DST", "destination", scalar.String(), warg.PositionalRequired()),
+			},
+			args:        []string{"copy", "a.txt"},
+			expectedErr: true,
+		},
+		{
+			name: "tooManyPositionals",
+			cmdOpts: []warg.CmdOpt{
+				warg.CmdPositional("SRC", "source", scalar.String()),
+			},
+			args:        []string{"copy", "a.txt", "b.txt"},
+			expectedErr: true,
+		},
+		{
+			name:        "noPositionalsDeclared",
+			args:        []string{"copy", "a.txt"},
+			expectedErr: true,
+		},
+		{
+			name: "typedPositional",
+			cmdOpts: []warg.CmdOpt{
+				warg.CmdPositional("COUNT", "count", scalar.Int()),
+			},
+			args:                      []string{"copy", "3"},
+			expectedPassedFlagValues:  warg.PassedFlags{"--help": "default"},
+			expectedPassedPositionals: warg.PassedFlags{"COUNT": 3},
+		},
+		{
+			name: "typedPositionalInvalid",
+			cmdOpts: []warg.CmdOpt{
+				warg.CmdPositional("COUNT", "count", scalar.Int()),
+			},
+			args:        []string{"copy", "three"},
+			expectedErr: true,
+		},
+		{
+			name: "positionalDefault",
+			cmdOpts: []warg.CmdOpt{
+				warg.CmdPositional("SRC", "source", scalar.String(), warg.PositionalRequired()),
+				warg.CmdPositional("DST", "destination", scalar.String(scalar.Default("."))),
+			},
+			args:                      []string{"copy", "a.txt"},
+			expectedPassedFlagValues:  warg.PassedFlags{"--help": "default"},
+			expectedPassedPositionals: warg.PassedFlags{"SRC": "a.txt", "DST": "."},
+		},
+		{
+			name: "positionalsInterleavedWithFlags",
+			cmdOpts: []warg.CmdOpt{
+				warg.CmdPositional("SRC", "source", scalar.String(), warg.PositionalRequired()),
+				warg.CmdPositional("DST", "destination", scalar.String(), warg.PositionalRequired()),
+				warg.NewCmdFlag("--mode", "copy mode", scalar.String()),
+			},
+			args:                      []string{"copy", "a.txt", "--mode", "fast", "b.txt"},
+			expectedPassedFlagValues:  warg.PassedFlags{"--help": "default", "--mode": "fast"},
+			expectedPassedPositionals: warg.PassedFlags{"SRC": "a.txt", "DST": "b.txt"},
+		},
+		{
+			name: "variadicPositional",
+			cmdOpts: []warg.CmdOpt{
+				warg.CmdPositional("DST", "destination", scalar.String(), warg.PositionalRequired()),
+				warg.CmdPositional("SRCS", "sources", slice.String(), warg.PositionalVariadic()),
+			},
+			args:                      []string{"copy", "dir", "a.txt", "b.txt", "c.txt"},
+			expectedPassedFlagValues:  warg.PassedFlags{"--help": "default"},
+			expectedPassedPositionals: warg.PassedFlags{"DST": "dir", "SRCS": []string{"a.txt", "b.txt", "c.txt"}},
+		},
+		{
+			name: "positionalChoices",
+			cmdOpts: []warg.CmdOpt{
+				warg.CmdPositional("MODE", "mode", scalar.String(scalar.Choices("fast", "slow"))),
+			},
+			args:        []string{"copy", "medium"},
+			expectedErr: true,
+		},
+		{
+			name: "dashPositional",
+			cmdOpts: []warg.CmdOpt{
+				warg.CmdPositional("SRC", "source", scalar.String()),
+			},
+			args:                      []string{"copy", "-"},
+			expectedPassedFlagValues:  warg.PassedFlags{"--help": "default"},
+			expectedPassedPositionals: warg.PassedFlags{"SRC": "-"},
+		},
+		{
+			name: "negativePositionalWithoutDoubleDash",
+			cmdOpts: []warg.CmdOpt{
+				warg.CmdPositional("COUNT", "count", scalar.Int()),
+			},
+			args:        []string{"copy", "-5"},
+			expectedErr: true,
+		},
+		{
+			name: "positionalsAfterDoubleDash",
+			cmdOpts: []warg.CmdOpt{
+				warg.CmdPositional("COUNT", "count", scalar.Int()),
+				warg.CmdPositional("NAMES", "names", slice.String(), warg.PositionalVariadic()),
+				warg.NewCmdFlag("--mode", "copy mode", scalar.String()),
+			},
+			args:                      []string{"copy", "--mode", "fast", "--", "-5", "--mode", "-"},
+			expectedPassedFlagValues:  warg.PassedFlags{"--help": "default", "--mode": "fast"},
+			expectedPassedPositionals: warg.PassedFlags{"COUNT": -5, "NAMES": []string{"--mode", "-"}},
+		},
+		{
+			name: "tooManyPositionalsAfterDoubleDash",
+			cmdOpts: []warg.CmdOpt{
+				warg.CmdPositional("SRC", "source", scalar.String()),
+			},
+			args:        []string{"copy", "--", "a.txt", "b.txt"},
+			expectedErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := warg.New(
+				"newAppName", "v1.0.0",
+				warg.NewSection(
+					"help for test",
+					warg.NewSubCmd(
+						"copy",
+						"help for copy",
+						warg.Unimplemented(),
+						tt.cmdOpts...,
+					),
+				),
+				warg.SkipAll(),
+			)
+
+			err := app.Validate()
+			require.Nil(t, err)
+
+			actualPR, actualErr := app.Parse(tt.args, warg.ParseWithLookupEnv(warg.LookupMap(nil)))
+
+			if tt.expectedErr {
+				require.Error(t, actualErr)
+				return
+			} else {
+				require.NoError(t, actualErr)
+			}
+			require.Equal(t, tt.expectedPassedFlagValues, actualPR.Context.Flags)
+			require.Equal(t, tt.expectedPassedPositionals, actualPR.Context.Positionals)
+		})
+	}
+}
+
+func TestApp_Parse_positionalSources(t *testing.T) {
+	app := warg.New(
+		"newAppName", "v1.0.0",
+		warg.NewSection(
+			"help for test",
+			warg.NewSubCmd(
+				"copy",
+				"help for copy",
+				warg.Unimplemented(),
+				warg.CmdPositional("DST", "destination", scalar.String()),
+				warg.CmdPositional("SRCS", "sources", slice.String(), warg.PositionalVariadic()),
+				warg.NewCmdFlag("--mode", "copy mode", scalar.String()),
+			),
+		),
+		warg.SkipAll(),
+	)
+	require.NoError(t, app.Validate())
+
+	pr, err := app.Parse([]string{"copy", "dir", "--mode", "fast", "a.txt", "b.txt"}, warg.ParseWithLookupEnv(warg.LookupMap(nil)))
+	require.NoError(t, err)
+	sources := pr.Context.ParseState.PositionalSources
+
+	require.Equal(t, []int{1}, sources["DST"].ArgIndexes)
+	require.Equal(t, []int{4, 5}, sources["SRCS"].ArgIndexes)
+	require.Equal(t, value.UpdatedByPositional, sources["SRCS"].UpdatedBy)
+	require.Equal(t, "args[4], args[5]", sources["SRCS"].Origin())
+}
+
+func TestApp_Parse_posixSyntax(t *testing.T) {
+	tests := []struct {
+		name                     string
+		args                     []string
+		expectedPassedFlagValues warg.PassedFlags
+		expectedErr              bool
+	}{
+		{
+			name:                     "flagEqualsValue",
+			args:                     []string{"test", "--count=3"},
+			expectedPassedFlagValues: warg.PassedFlags{"--count": 3, "--help": "default"},
+			expectedErr:              false,
+		},
+		{
+			name:                     "aliasEqualsValue",
+			args:                     []string{"test", "-c=3"},
+			expectedPassedFlagValues: warg.PassedFlags{"--count": 3, "--help": "default"},
+			expectedErr:              false,
+		},
+		{
+			name:                     "flagEqualsEmptyValue",
+			args:                     []string{"test", "--name="},
+			expectedPassedFlagValues: warg.PassedFlags{"--name": "", "--help": "default"},
+			expectedErr:              false,
+		},
+		{
+			name:                     "flagEqualsDictValue",
+			args:                     []string{"test", "--label=key=value"},
+			expectedPassedFlagValues: warg.PassedFlags{"--label": map[string]string{"key": "value"}, "--help": "default"},
+			expectedErr:              false,
+		},
+		{
+			name:                     "flagEqualsUnknownFlag",
+			args:                     []string{"test", "--nope=3"},
+			expectedPassedFlagValues: nil,
+			expectedErr:              true,
+		},
+		{
+			name:                     "bundledBoolAliases",
+			args:                     []string{"test", "-ab"},
+			expectedPassedFlagValues: warg.PassedFlags{"--all": true, "--brief": true, "--help": "default"},
+			expectedErr:              false,
+		},
+		{
+			name:                     "bundledRepeatedSliceBoolAlias",
+			args:                     []string{"test", "-vvv"},
+			expectedPassedFlagValues: warg.PassedFlags{"--verbose": []bool{true, true, true}, "--help": "default"},
+			expectedErr:              false,
+		},
+		{
+			name:                     "bundledAliasAttachedValue",
+			args:                     []string{"test", "-ac3"},
+			expectedPassedFlagValues: warg.PassedFlags{"--all": true, "--count": 3, "--help": "default"},
+			expectedErr:              false,
+		},
+		{
+			name:                     "bundledAliasNextArgValue",
+			args:                     []string{"test", "-ac", "3"},
+			expectedPassedFlagValues: warg.PassedFlags{"--all": true, "--count": 3, "--help": "default"},
+			expectedErr:              false,
+		},
+		{
+			name:                     "bundledNonSwitchBoolNeedsValue",
+			args:                     []string{"test", "-af"},
+			expectedPassedFlagValues: nil,
+			expectedErr:              true,
+		},
+		{
+			name:                     "bundledNonSwitchBoolNextArgValue",
+			args:                     []string{"test", "-af", "false"},
+			expectedPassedFlagValues: warg.PassedFlags{"--all": true, "--force": false, "--help": "default"},
+			expectedErr:              false,
+		},
+		{
+			name:                     "bundledUnknownAlias",
+			args:                     []string{"test", "-az"},
+			expectedPassedFlagValues: nil,
+			expectedErr:              true,
+		},
+		{
+			name:                     "helpEqualsValue",
+			args:                     []string{"test", "--help=detailed"},
+			expectedPassedFlagValues: warg.PassedFlags{"--help": "detailed"},
+			expectedErr:              false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := warg.New(
+				"newAppName", "v1.0.0",
+				warg.NewSection(
+					"help for test",
+					warg.NewSubCmd(
+						"test",
+						"help for test",
+						warg.Unimplemented(),
+						warg.NewCmdFlag("--all", "all", scalar.Bool(), warg.Alias("-a"), warg.Switch()),
+						warg.NewCmdFlag("--brief", "brief", scalar.Bool(), warg.Alias("-b"), warg.Switch()),
+						warg.NewCmdFlag("--count", "count", scalar.Int(), warg.Alias("-c")),
+						warg.NewCmdFlag("--force", "force", scalar.Bool(), warg.Alias("-f")),
+						warg.NewCmdFlag("--label", "label", dict.String()),
+						warg.NewCmdFlag("--name", "name", scalar.String()),
+						warg.NewCmdFlag("--verbose", "verbosity", slice.Bool(), warg.Alias("-v"), warg.Switch()),
+					),
+				),
+				warg.SkipAll(),
+			)
+
+			err := app.Validate()
+			require.Nil(t, err)
+
+			actualPR, actualErr := app.Parse(tt.args, warg.ParseWithLookupEnv(warg.LookupMap(nil)))
+
+			if tt.expectedErr {
+				require.Error(t, actualErr)
+				return
+			} else {
+				require.NoError(t, actualErr)
+			}
+			require.Equal(t, tt.expectedPassedFlagValues, actualPR.Context.Flags)
+		})
+	}
+}
+
+func TestApp_Parse_switch(t *testing.T) {
+	tests := []struct {
+		name                     string
+		args                     []string
+		expectedPassedFlagValues warg.PassedFlags
+		expectedErr              bool
+	}{
+		{
+			name:                     "switchDefault",
+			args:                     []string{"test"},
+			expectedPassedFlagValues: warg.PassedFlags{"--color": true, "--help": "default"},
+			expectedErr:              false,
+		},
+		{
+			name:                     "switchPassed",
+			args:                     []string{"test", "--verbose"},
+			expectedPassedFlagValues: warg.PassedFlags{"--color": true, "--verbose": true, "--help": "default"},
+			expectedErr:              false,
+		},
+		{
+			name:                     "switchAliasPassed",
+			args:                     []string{"test", "-v"},
+			expectedPassedFlagValues: warg.PassedFlags{"--color": true, "--verbose": true, "--help": "default"},
+			expectedErr:              false,
+		},
+		{
+			name:                     "switchExplicitValue",
+			args:                     []string{"test", "--verbose=false"},
+			expectedPassedFlagValues: warg.PassedFlags{"--color": true, "--verbose": false, "--help": "default"},
+			expectedErr:              false,
+		},
+		{
+			name:                     "switchDoesNotConsumeNextArg",
+			args:                     []string{"test", "--verbose", "false"},
+			expectedPassedFlagValues: nil,
+			expectedErr:              true,
+		},
+		{
+			name:                     "negated",
+			args:                     []string{"test", "--no-color"},
+			expectedPassedFlagValues: warg.PassedFlags{"--color": false, "--help": "default"},
+			expectedErr:              false,
+		},
+		{
+			name:                     "negatedNotNegatable",
+			args:                     []string{"test", "--no-verbose"},
+			expectedPassedFlagValues: nil,
+			expectedErr:              true,
+		},
+		{
+			name:                     "negatedAndPassed",
+			args:                     []string{"test", "--no-color", "--color"},
+			expectedPassedFlagValues: nil,
+			expectedErr:              true,
+		},
+		{
+			name:                     "unsetSentinel",
+			args:                     []string{"test", "--color=UNSET"},
+			expectedPassedFlagValues: warg.PassedFlags{"--help": "default"},
+			expectedErr:              false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := warg.New(
+				"newAppName", "v1.0.0",
+				warg.NewSection(
+					"help for test",
+					warg.NewSubCmd(
+						"test",
+						"help for test",
+						warg.Unimplemented(),
+						warg.NewCmdFlag("--color", "color", scalar.Bool(scalar.Default(true)), warg.Negatable(), warg.UnsetSentinel("UNSET")),
+						warg.NewCmdFlag("--verbose", "verbose", scalar.Bool(), warg.Alias("-v"), warg.Switch()),
+					),
+				),
+				warg.SkipAll(),
+			)
+
+			err := app.Validate()
+			require.Nil(t, err)
+
+			actualPR, actualErr := app.Parse(tt.args, warg.ParseWithLookupEnv(warg.LookupMap(nil)))
+
+			if tt.expectedErr {
+				require.Error(t, actualErr)
+				return
+			} else {
+				require.NoError(t, actualErr)
+			}
+			require.Equal(t, tt.expectedPassedFlagValues, actualPR.Context.Flags)
+		})
+	}
+}
+
 func TestApp_Parse_config(t *testing.T) {
 	tests := []struct {
 		name                     string
@@ -979,6 +1398,1200 @@ func TestApp_Parse_config(t *testing.T) {
 	}
 }
 
+func TestApp_Parse_configFiles(t *testing.T) {
+	newApp := func(opts ...warg.AppOpt) warg.App {
+		flagOpts := []warg.CmdOpt{}
+		for _, name := range []string{"a", "b", "c"} {
+			flagOpts = append(flagOpts, warg.NewCmdFlag(
+				"--"+name,
+				name+" help",
+				scalar.String(),
+				warg.ConfigPath(name),
+			))
+		}
+		return warg.New(
+			"newAppName", "v1.0.0",
+			warg.NewSection(
+				"help for test",
+				warg.NewSubCmd(
+					"com",
+					"help for com",
+					warg.Unimplemented(),
+					flagOpts...,
+				),
+			),
+			append(opts, warg.SkipAll())...,
+		)
+	}
+	layeredFile := func(name string) string {
+		return testDataFilePath(t.Name(), "layered", name).MustExpand()
+	}
+
+	tests := []struct {
+		name                string
+		app                 warg.App
+		args                []string
+		expectedPassedFlags warg.PassedFlags
+		expectedFlagSources map[string]warg.FlagSource
+	}{
+		{
+			name: "layered",
+			app: newApp(
+				warg.ConfigFile(yamlreader.New, layeredFile("system.yaml")),
+				warg.ConfigFile(jsonreader.New, layeredFile("user.json")),
+				warg.ConfigFile(jsonreader.New, layeredFile("does-not-exist.json")),
+			),
+			args: []string{"com"},
+			expectedPassedFlags: warg.PassedFlags{
+				"--a":    "system",
+				"--b":    "user",
+				"--c":    "user",
+				"--help": "default",
+			},
+			expectedFlagSources: map[string]warg.FlagSource{
+				"--a": {
+					UpdatedBy:      value.UpdatedByConfig,
+					ArgIndexes:     nil,
+					EnvVar:         "",
+					DotEnvFilePath: "",
+					ConfigFilePath: layeredFile("system.yaml"),
+					ConfigPath:     "a",
+				},
+				"--b": {
+					UpdatedBy:      value.UpdatedByConfig,
+					ArgIndexes:     nil,
+					EnvVar:         "",
+					DotEnvFilePath: "",
+					ConfigFilePath: layeredFile("user.json"),
+					ConfigPath:     "b",
+				},
+				"--c": {
+					UpdatedBy:      value.UpdatedByConfig,
+					ArgIndexes:     nil,
+					EnvVar:         "",
+					DotEnvFilePath: "",
+					ConfigFilePath: layeredFile("user.json"),
+					ConfigPath:     "c",
+				},
+				"--help": {
+					UpdatedBy:      value.UpdatedByDefault,
+					ArgIndexes:     nil,
+					EnvVar:         "",
+					DotEnvFilePath: "",
+					ConfigFilePath: "",
+					ConfigPath:     "",
+				},
+			},
+		},
+		{
+			name: "configFlagTakesPrecedence",
+			app: newApp(
+				warg.ConfigFile(yamlreader.New, layeredFile("system.yaml")),
+				warg.ConfigFile(jsonreader.New, layeredFile("user.json")),
+				warg.ConfigFlag(
+					tomlreader.New,
+					warg.FlagMap{
+						"--config": warg.NewFlag(
+							"path to config",
+							scalar.Path(),
+						),
+					},
+				),
+			),
+			args: []string{"com", "--b", "passed", "--config", layeredFile("project.toml")},
+			expectedPassedFlags: warg.PassedFlags{
+				"--a":      "system",
+				"--b":      "passed",
+				"--c":      "project",
+				"--config": path.New(layeredFile("project.toml")),
+				"--help":   "default",
+			},
+			expectedFlagSources: map[string]warg.FlagSource{
+				"--a": {
+					UpdatedBy:      value.UpdatedByConfig,
+					ArgIndexes:     nil,
+					EnvVar:         "",
+					DotEnvFilePath: "",
+					ConfigFilePath: layeredFile("system.yaml"),
+					ConfigPath:     "a",
+				},
+				"--b": {
+					UpdatedBy:      value.UpdatedByFlag,
+					ArgIndexes:     []int{2},
+					EnvVar:         "",
+					DotEnvFilePath: "",
+					ConfigFilePath: "",
+					ConfigPath:     "",
+				},
+				"--c": {
+					UpdatedBy:      value.UpdatedByConfig,
+					ArgIndexes:     nil,
+					EnvVar:         "",
+					DotEnvFilePath: "",
+					ConfigFilePath: layeredFile("project.toml"),
+					ConfigPath:     "c",
+				},
+				"--config": {
+					UpdatedBy:      value.UpdatedByFlag,
+					ArgIndexes:     []int{4},
+					EnvVar:         "",
+					DotEnvFilePath: "",
+					ConfigFilePath: "",
+					ConfigPath:     "",
+				},
+				"--help": {
+					UpdatedBy:      value.UpdatedByDefault,
+					ArgIndexes:     nil,
+					EnvVar:         "",
+					DotEnvFilePath: "",
+					ConfigFilePath: "",
+					ConfigPath:     "",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.app.Validate()
+			require.Nil(t, err)
+
+			actualPR, err := tt.app.Parse(tt.args, warg.ParseWithLookupEnv(warg.LookupMap(nil)))
+			require.Nil(t, err)
+			require.Equal(t, tt.expectedPassedFlags, actualPR.Context.Flags)
+			require.Equal(t, tt.expectedFlagSources, actualPR.Context.ParseState.FlagSources)
+		})
+	}
+}
+
+func TestApp_Parse_envPrefix(t *testing.T) {
+	app := warg.New(
+		"newAppName", "v1.0.0",
+		warg.NewSection(
+			"help for test",
+			warg.NewSubSection(
+				"db",
+				"database commands",
+				warg.NewSubCmd(
+					"migrate",
+					"migrate the database",
+					warg.Unimplemented(),
+					warg.NewCmdFlag(
+						"--db-url",
+						"database URL",
+						scalar.String(),
+					),
+					warg.NewCmdFlag(
+						"--timeout",
+						"timeout",
+						scalar.Int(),
+						warg.EnvVars("TIMEOUT"),
+					),
+				),
+			),
+		),
+		warg.NewGlobalFlag(
+			"--verbose",
+			"verbose",
+			scalar.Bool(),
+		),
+		warg.EnvPrefix("MYAPP"),
+		warg.SkipAll(),
+	)
+
+	tests := []struct {
+		name                string
+		lookup              map[string]string
+		expectedPassedFlags warg.PassedFlags
+	}{
+		{
+			name: "cmdScoped",
+			lookup: map[string]string{
+				"MYAPP_DB_MIGRATE_DB_URL": "scoped",
+				"MYAPP_DB_URL":            "global",
+				"MYAPP_VERBOSE":           "true",
+			},
+			expectedPassedFlags: warg.PassedFlags{
+				"--db-url":  "scoped",
+				"--verbose": true,
+				"--help":    "default",
+			},
+		},
+		{
+			name: "globalForm",
+			lookup: map[string]string{
+				"MYAPP_DB_URL": "global",
+			},
+			expectedPassedFlags: warg.PassedFlags{
+				"--db-url": "global",
+				"--help":   "default",
+			},
+		},
+		{
+			name: "explicitEnvVarsFirst",
+			lookup: map[string]string{
+				"TIMEOUT":                  "1",
+				"MYAPP_DB_MIGRATE_TIMEOUT": "2",
+			},
+			expectedPassedFlags: warg.PassedFlags{
+				"--timeout": 1,
+				"--help":    "default",
+			},
+		},
+		{
+			name: "helpFlagNotDerived",
+			lookup: map[string]string{
+				"MYAPP_HELP": "detailed",
+			},
+			expectedPassedFlags: warg.PassedFlags{
+				"--help": "default",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := app.Validate()
+			require.Nil(t, err)
+
+			actualPR, err := app.Parse([]string{"db", "migrate"}, warg.ParseWithLookupEnv(warg.LookupMap(tt.lookup)))
+			require.Nil(t, err)
+			require.Equal(t, tt.expectedPassedFlags, actualPR.Context.Flags)
+		})
+	}
+}
+
+func TestApp_Parse_dotEnv(t *testing.T) {
+	dotEnvFile := func(name string) string {
+		return filepath.Join("testdata", t.Name(), name)
+	}
+	app := warg.New(
+		"newAppName", "v1.0.0",
+		warg.NewSection(
+			"help for test",
+			warg.NewSubCmd(
+				"com",
+				"help for com",
+				warg.Unimplemented(),
+				warg.NewCmdFlag("--db-url", "database URL", scalar.String(), warg.EnvVars("DB_URL")),
+				warg.NewCmdFlag("--log-level", "log level", scalar.String(), warg.EnvVars("LOG_LEVEL")),
+				warg.NewCmdFlag("--token", "token", scalar.String(), warg.EnvVars("TOKEN")),
+				warg.NewCmdFlag("--cache-url", "cache URL", scalar.String(), warg.EnvVars("CACHE_URL")),
+			),
+		),
+		warg.DotEnvFile(dotEnvFile("base.env")),
+		warg.DotEnvFile(dotEnvFile("local.env")),
+		warg.DotEnvFile(dotEnvFile("does-not-exist.env")),
+		warg.SkipAll(),
+	)
+
+	err := app.Validate()
+	require.Nil(t, err)
+
+	actualPR, err := app.Parse([]string{"com"}, warg.ParseWithLookupEnv(warg.LookupMap(map[string]string{"TOKEN": "from-env"})))
+	require.Nil(t, err)
+
+	require.Equal(t, warg.PassedFlags{
+		"--cache-url": "redis://cache-host/from-env",
+		"--db-url":    "postgres://localhost/app",
+		"--log-level": "debug",
+		"--token":     "from-env",
+		"--help":      "default",
+	}, actualPR.Context.Flags)
+
+	sources := actualPR.Context.ParseState.FlagSources
+	delete(sources, "--help")
+	require.Equal(t, map[string]warg.FlagSource{
+		"--cache-url": {
+			UpdatedBy:      value.UpdatedByEnvVar,
+			ArgIndexes:     nil,
+			EnvVar:         "CACHE_URL",
+			DotEnvFilePath: dotEnvFile("local.env"),
+			ConfigFilePath: "",
+			ConfigPath:     "",
+		},
+		"--db-url": {
+			UpdatedBy:      value.UpdatedByEnvVar,
+			ArgIndexes:     nil,
+			EnvVar:         "DB_URL",
+			DotEnvFilePath: dotEnvFile("base.env"),
+			ConfigFilePath: "",
+			ConfigPath:     "",
+		},
+		"--log-level": {
+			UpdatedBy:      value.UpdatedByEnvVar,
+			ArgIndexes:     nil,
+			EnvVar:         "LOG_LEVEL",
+			DotEnvFilePath: dotEnvFile("local.env"),
+			ConfigFilePath: "",
+			ConfigPath:     "",
+		},
+		"--token": {
+			UpdatedBy:      value.UpdatedByEnvVar,
+			ArgIndexes:     nil,
+			EnvVar:         "TOKEN",
+			DotEnvFilePath: "",
+			ConfigFilePath: "",
+			ConfigPath:     "",
+		},
+	}, sources)
+}
+
+func TestApp_Parse_flagConstraints(t *testing.T) {
+	app := warg.New(
+		"newAppName", "v1.0.0",
+		warg.NewSection(
+			"help for test",
+			warg.NewSubCmd(
+				"com",
+				"help for com",
+				warg.Unimplemented(),
+				warg.NewCmdFlag("--json", "json output", scalar.Bool(), warg.EnvVars("JSON")),
+				warg.NewCmdFlag("--table", "table output", scalar.Bool()),
+				warg.NewCmdFlag("--user", "user", scalar.String()),
+				warg.NewCmdFlag("--password", "password", scalar.String()),
+				warg.NewCmdFlag("--id", "id", scalar.Int()),
+				warg.NewCmdFlag("--name", "name", scalar.String(scalar.Default("default"))),
+				warg.MutuallyExclusive("--json", "--table"),
+				warg.RequiredTogether("--user", "--password"),
+				warg.AtLeastOneOf("--id", "--name"),
+			),
+		),
+		warg.SkipAll(),
+	)
+
+	tests := []struct {
+		name          string
+		args          []string
+		lookup        map[string]string
+		expectedErrIs string
+	}{
+		{
+			name:          "satisfied",
+			args:          []string{"com", "--json", "true", "--user", "u", "--password", "p", "--id", "1"},
+			lookup:        nil,
+			expectedErrIs: "",
+		},
+		{
+			name:          "mutuallyExclusive",
+			args:          []string{"com", "--table", "true", "--id", "1"},
+			lookup:        map[string]string{"JSON": "true"},
+			expectedErrIs: "Flags are mutually exclusive but got: --json (envvar: JSON), --table (passedflag: args[2])",
+		},
+		{
+			name:          "requiredTogether",
+			args:          []string{"com", "--user", "u", "--id", "1"},
+			lookup:        nil,
+			expectedErrIs: "Flags are required together but got: --user (passedflag: args[2]); missing: --password",
+		},
+		{
+			name:          "atLeastOneOfIgnoresDefaults",
+			args:          []string{"com"},
+			lookup:        nil,
+			expectedErrIs: "At least one of these flags is required: --id, --name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := app.Validate()
+			require.Nil(t, err)
+
+			_, err = app.Parse(tt.args, warg.ParseWithLookupEnv(warg.LookupMap(tt.lookup)))
+			if tt.expectedErrIs == "" {
+				require.Nil(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.expectedErrIs)
+		})
+	}
+}
+
+func TestApp_Parse_flagRules(t *testing.T) {
+	app := warg.New(
+		"newAppName", "v1.0.0",
+		warg.NewSection(
+			"help for test",
+			warg.NewSubCmd(
+				"com",
+				"help for com",
+				warg.Unimplemented(),
+				warg.NewCmdFlag("--mode", "mode", scalar.String(scalar.Default("plain")), warg.ConfigPath("mode")),
+				warg.NewCmdFlag("--cert", "cert", scalar.String()),
+				warg.NewCmdFlag("--key-file", "key file", scalar.String()),
+				warg.NewCmdFlag("--key-password", "key password", scalar.String(), warg.EnvVars("KEY_PASSWORD")),
+				warg.NewCmdFlag("--cipher", "cipher", scalar.String()),
+				warg.NewCmdFlag("--tls-version", "TLS version", scalar.String(scalar.Default("1.3"))),
+				warg.RequiredIf("--cert", "--mode", "tls"),
+				warg.DependsOn("--key-password", "--key-file"),
+				warg.DependsOn("--cipher", "--tls-version"),
+			),
+		),
+		warg.ConfigFile(yamlreader.New, filepath.Join("testdata", "TestApp_Parse_flagRules", "config.yaml")),
+		warg.SkipAll(),
+	)
+
+	tests := []struct {
+		name          string
+		args          []string
+		lookup        map[string]string
+		expectedErrIs string
+	}{
+		{
+			name:          "satisfied",
+			args:          []string{"com", "--cert", "cert.pem", "--key-file", "key.pem", "--key-password", "secret"},
+			lookup:        nil,
+			expectedErrIs: "",
+		},
+		{
+			name:          "requiredIfFromConfig",
+			args:          []string{"com"},
+			lookup:        nil,
+			expectedErrIs: `Flag is required because --mode (config: testdata/TestApp_Parse_flagRules/config.yaml: mode) is "tls": --cert`,
+		},
+		{
+			name:          "requiredIfNotTriggered",
+			args:          []string{"com", "--mode", "plain"},
+			lookup:        nil,
+			expectedErrIs: "",
+		},
+		{
+			name:          "dependsOnFromEnv",
+			args:          []string{"com", "--cert", "cert.pem"},
+			lookup:        map[string]string{"KEY_PASSWORD": "secret"},
+			expectedErrIs: "Flag is only valid when --key-file is set: --key-password (envvar: KEY_PASSWORD)",
+		},
+		{
+			name:          "dependsOnIgnoresDefault",
+			args:          []string{"com", "--cert", "cert.pem", "--cipher", "aes"},
+			lookup:        nil,
+			expectedErrIs: "Flag is only valid when --tls-version is set: --cipher (passedflag: args[4])",
+		},
+		{
+			name:          "dependsOnSatisfied",
+			args:          []string{"com", "--cert", "cert.pem", "--cipher", "aes", "--tls-version", "1.3"},
+			lookup:        nil,
+			expectedErrIs: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := app.Validate()
+			require.Nil(t, err)
+
+			_, err = app.Parse(tt.args, warg.ParseWithLookupEnv(warg.LookupMap(tt.lookup)))
+			if tt.expectedErrIs == "" {
+				require.Nil(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.expectedErrIs)
+		})
+	}
+}
+
+func TestApp_Parse_validators(t *testing.T) {
+	portValidator := func(v any) error {
+		if v.(int) > 65535 {
+			return errors.New("port must be <= 65535")
+		}
+		return nil
+	}
+	app := warg.New(
+		"newAppName", "v1.0.0",
+		warg.NewSection(
+			"help for test",
+			warg.NewSubCmd(
+				"com",
+				"help for com",
+				warg.Unimplemented(),
+				warg.NewCmdFlag("--port", "port", scalar.Int(scalar.Default(80)), warg.FlagValidator(portValidator), warg.EnvVars("PORT")),
+				warg.NewCmdFlag("--admin-port", "admin port", scalar.Int(), warg.FlagValidator(portValidator)),
+				warg.NewCmdFlag("--no-admin", "disable admin", scalar.Bool()),
+				warg.MutuallyExclusive("--admin-port", "--no-admin"),
+				warg.CmdValidator(func(cmdCtx warg.CmdContext) error {
+					adminPort, exists := cmdCtx.Flags["--admin-port"]
+					if exists && adminPort.(int) == cmdCtx.Flags["--port"].(int) {
+						return errors.New("--port and --admin-port must be different")
+					}
+					return nil
+				}),
+			),
+		),
+		warg.SkipAll(),
+	)
+
+	tests := []struct {
+		name           string
+		args           []string
+		lookup         map[string]string
+		expectedErrIns []string
+		// unexpectedErrIns are messages from command validators, which don't run when flags are missing or conflict
+		unexpectedErrIns []string
+	}{
+		{
+			name:             "valid",
+			args:             []string{"com", "--admin-port", "8080"},
+			lookup:           nil,
+			expectedErrIns:   nil,
+			unexpectedErrIns: nil,
+		},
+		{
+			name:   "allErrorsReported",
+			args:   []string{"com", "--admin-port", "70000"},
+			lookup: map[string]string{"PORT": "70000"},
+			expectedErrIns: []string{
+				"Invalid value for flag: --admin-port (passedflag: args[2])",
+				"Invalid value for flag: --port (envvar: PORT)",
+				"port must be <= 65535",
+				"--port and --admin-port must be different",
+			},
+			unexpectedErrIns: nil,
+		},
+		{
+			name:   "constraintAndValidatorErrorsReported",
+			args:   []string{"com", "--admin-port", "8080", "--no-admin", "true"},
+			lookup: map[string]string{"PORT": "70000"},
+			expectedErrIns: []string{
+				"Flags are mutually exclusive but got: --admin-port (passedflag: args[2]), --no-admin (passedflag: args[4])",
+				"Invalid value for flag: --port (envvar: PORT)",
+			},
+			unexpectedErrIns: nil,
+		},
+		{
+			name:             "cmdValidatorsSkippedOnConstraintError",
+			args:             []string{"com", "--admin-port", "80", "--no-admin", "true"},
+			lookup:           nil,
+			expectedErrIns:   []string{"Flags are mutually exclusive"},
+			unexpectedErrIns: []string{"--port and --admin-port must be different"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := app.Validate()
+			require.Nil(t, err)
+
+			_, err = app.Parse(tt.args, warg.ParseWithLookupEnv(warg.LookupMap(tt.lookup)))
+			if tt.expectedErrIns == nil {
+				require.Nil(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, expected := range tt.expectedErrIns {
+				require.Contains(t, err.Error(), expected)
+			}
+			for _, unexpected := range tt.unexpectedErrIns {
+				require.NotContains(t, err.Error(), unexpected)
+			}
+		})
+	}
+}
+
+func TestApp_Parse_valueConstraints(t *testing.T) {
+	app := warg.New(
+		"newAppName", "v1.0.0",
+		warg.NewSection(
+			"help for test",
+			warg.NewSubCmd(
+				"com",
+				"help for com",
+				warg.Unimplemented(),
+				warg.NewCmdFlag("--host", "hosts", slice.String(slice.MinLen[string](2))),
+				warg.NewCmdFlag("--port", "port", scalar.Int(scalar.Max(65535)), warg.EnvVars("PORT")),
+			),
+		),
+		warg.SkipAll(),
+	)
+
+	err := app.Validate()
+	require.Nil(t, err)
+
+	_, err = app.Parse([]string{"com", "--host", "a", "--host", "b"}, warg.ParseWithLookupEnv(warg.LookupMap(nil)))
+	require.Nil(t, err)
+
+	_, err = app.Parse([]string{"com", "--host", "a"}, warg.ParseWithLookupEnv(warg.LookupMap(nil)))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Invalid value for flag: --host (passedflag: args[2])")
+	require.Contains(t, err.Error(), "minlen: 2")
+
+	_, err = app.Parse([]string{"com", "--host", "a", "--host", "b"}, warg.ParseWithLookupEnv(warg.LookupMap(map[string]string{"PORT": "70000"})))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "max: 65535")
+}
+
+func TestApp_Parse_aliases(t *testing.T) {
+	tests := []struct {
+		name                string
+		args                []string
+		lookup              map[string]string
+		configFile          string
+		expectedPassedFlags warg.PassedFlags
+	}{
+		{
+			name:       "renamedConfigPath",
+			args:       []string{"db", "rm", "--yes", "true"},
+			lookup:     nil,
+			configFile: filepath.Join("testdata", "TestApp_Parse_aliases", "config.yaml"),
+			expectedPassedFlags: warg.PassedFlags{
+				"--database-url": "from-old-config-path",
+				"--force":        true,
+				"--help":         "default",
+			},
+		},
+		{
+			name:       "renamedFlagName",
+			args:       []string{"database", "delete", "--db", "from-old-flag", "-y", "true"},
+			lookup:     nil,
+			configFile: "",
+			expectedPassedFlags: warg.PassedFlags{
+				"--database-url": "from-old-flag",
+				"--force":        true,
+				"--help":         "default",
+			},
+		},
+		{
+			name:       "renamedEnvVar",
+			args:       []string{"db", "remove"},
+			lookup:     map[string]string{"DB": "from-old-env-var"},
+			configFile: "",
+			expectedPassedFlags: warg.PassedFlags{
+				"--database-url": "from-old-env-var",
+				"--help":         "default",
+			},
+		},
+		{
+			name:       "renamedDerivedEnvVar",
+			args:       []string{"db", "remove"},
+			lookup:     map[string]string{"MYAPP_DB": "from-old-derived-env-var"},
+			configFile: "",
+			expectedPassedFlags: warg.PassedFlags{
+				"--database-url": "from-old-derived-env-var",
+				"--help":         "default",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appOpts := []warg.AppOpt{
+				warg.EnvPrefix("MYAPP"),
+				warg.SkipAll(),
+			}
+			if tt.configFile != "" {
+				appOpts = append(appOpts, warg.ConfigFile(yamlreader.New, tt.configFile))
+			}
+			app := warg.New(
+				"newAppName", "v1.0.0",
+				warg.NewSection(
+					"help for test",
+					warg.NewSubSection(
+						"database",
+						"database commands",
+						warg.SectionAliases("db"),
+						warg.NewSubCmd(
+							"remove",
+							"remove a database",
+							warg.Unimplemented(),
+							warg.CmdAliases("rm", "delete"),
+							warg.NewCmdFlag(
+								"--database-url",
+								"database URL",
+								scalar.String(),
+								warg.ConfigPath("database.url"),
+								warg.RenamedFrom("--db", warg.OldConfigPath("db"), warg.OldEnvVars("DB")),
+							),
+							warg.NewCmdFlag(
+								"--force",
+								"force",
+								scalar.Bool(),
+								warg.Alias("-f"),
+								warg.Aliases("-y", "--yes"),
+							),
+						),
+					),
+				),
+				appOpts...,
+			)
+
+			err := app.Validate()
+			require.Nil(t, err)
+
+			actualPR, err := app.Parse(tt.args, warg.ParseWithLookupEnv(warg.LookupMap(tt.lookup)))
+			require.Nil(t, err)
+			require.Equal(t, tt.expectedPassedFlags, actualPR.Context.Flags)
+			require.Equal(t, []string{"database"}, actualPR.Context.ParseState.SectionPath)
+			require.Equal(t, "remove", actualPR.Context.ParseState.CurrentCmdName)
+		})
+	}
+}
+
+func TestApp_Parse_prefixMatching(t *testing.T) {
+	newApp := func(opts ...warg.AppOpt) warg.App {
+		return warg.New(
+			"newAppName", "v1.0.0",
+			warg.NewSection(
+				"help for test",
+				warg.NewSubCmd("start", "start", warg.Unimplemented()),
+				warg.NewSubCmd(
+					"status",
+					"status",
+					warg.Unimplemented(),
+					warg.NewCmdFlag("--verbose", "verbose", scalar.Bool()),
+					warg.NewCmdFlag("--version-check", "version check", scalar.Bool()),
+					warg.NewCmdFlag("--output", "output", scalar.String(), warg.Aliases("--out-file")),
+					warg.NewCmdFlag("--color", "color", scalar.Bool(), warg.Switch(), warg.Negatable()),
+				),
+				warg.NewSubCmd("sync", "sync", warg.Unimplemented()),
+				warg.NewSubCmd("sync-all", "sync all", warg.Unimplemented()),
+				warg.NewSubCmd("stop-everything", "stop", warg.Unimplemented(), warg.CmdHidden()),
+				warg.NewSubSection(
+					"database",
+					"database commands",
+					warg.SectionAliases("postgres"),
+					warg.NewSubCmd("migrate", "migrate", warg.Unimplemented()),
+				),
+			),
+			opts...,
+		)
+	}
+
+	tests := []struct {
+		name                string
+		app                 warg.App
+		args                []string
+		expectedCmdName     string
+		expectedPassedFlags warg.PassedFlags
+		expectedErr         bool
+	}{
+		{
+			name:                "disabledByDefault",
+			app:                 newApp(warg.SkipAll()),
+			args:                []string{"stat"},
+			expectedCmdName:     "",
+			expectedPassedFlags: nil,
+			expectedErr:         true,
+		},
+		{
+			name:                "uniqueCmdPrefix",
+			app:                 newApp(warg.SkipAll(), warg.AllowPrefixMatching()),
+			args:                []string{"stat"},
+			expectedCmdName:     "status",
+			expectedPassedFlags: warg.PassedFlags{"--help": "default"},
+			expectedErr:         false,
+		},
+		{
+			name:                "ambiguousCmdPrefix",
+			app:                 newApp(warg.SkipAll(), warg.AllowPrefixMatching()),
+			args:                []string{"sta"},
+			expectedCmdName:     "",
+			expectedPassedFlags: nil,
+			expectedErr:         true,
+		},
+		{
+			name:                "exactMatchWins",
+			app:                 newApp(warg.SkipAll(), warg.AllowPrefixMatching()),
+			args:                []string{"sync"},
+			expectedCmdName:     "sync",
+			expectedPassedFlags: warg.PassedFlags{"--help": "default"},
+			expectedErr:         false,
+		},
+		{
+			name:                "hiddenNotMatched",
+			app:                 newApp(warg.SkipAll(), warg.AllowPrefixMatching()),
+			args:                []string{"stop"},
+			expectedCmdName:     "",
+			expectedPassedFlags: nil,
+			expectedErr:         true,
+		},
+		{
+			name:                "sectionAliasPrefix",
+			app:                 newApp(warg.SkipAll(), warg.AllowPrefixMatching()),
+			args:                []string{"post", "mig"},
+			expectedCmdName:     "migrate",
+			expectedPassedFlags: warg.PassedFlags{"--help": "default"},
+			expectedErr:         false,
+		},
+		{
+			name:                "uniqueFlagPrefix",
+			app:                 newApp(warg.SkipAll(), warg.AllowPrefixMatching()),
+			args:                []string{"status", "--verb", "true", "--out-f", "out.txt"},
+			expectedCmdName:     "status",
+			expectedPassedFlags: warg.PassedFlags{"--help": "default", "--verbose": true, "--output": "out.txt"},
+			expectedErr:         false,
+		},
+		{
+			name:                "negatedFlagPrefix",
+			app:                 newApp(warg.SkipAll(), warg.AllowPrefixMatching()),
+			args:                []string{"status", "--no-col"},
+			expectedCmdName:     "status",
+			expectedPassedFlags: warg.PassedFlags{"--help": "default", "--color": false},
+			expectedErr:         false,
+		},
+		{
+			name:                "ambiguousFlagPrefix",
+			app:                 newApp(warg.SkipAll(), warg.AllowPrefixMatching()),
+			args:                []string{"status", "--ver", "true"},
+			expectedCmdName:     "",
+			expectedPassedFlags: nil,
+			expectedErr:         true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.app.Validate()
+			require.Nil(t, err)
+
+			actualPR, err := tt.app.Parse(tt.args, warg.ParseWithLookupEnv(warg.LookupMap(nil)))
+			if tt.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.expectedCmdName, actualPR.Context.ParseState.CurrentCmdName)
+			require.Equal(t, tt.expectedPassedFlags, actualPR.Context.Flags)
+		})
+	}
+}
+
+func TestApp_Parse_context(t *testing.T) {
+	type ctxKey struct{}
+
+	app := warg.New(
+		"newAppName", "v1.0.0",
+		warg.NewSection(
+			"help for test",
+			warg.NewSubCmd("com", "com", warg.Unimplemented()),
+		),
+		warg.SkipAll(),
+	)
+
+	t.Run("default", func(t *testing.T) {
+		pr, err := app.Parse([]string{"com"}, warg.ParseWithLookupEnv(warg.LookupMap(nil)))
+		require.Nil(t, err)
+		require.Equal(t, context.Background(), pr.Context.Context)
+	})
+
+	t.Run("parseWithContext", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+		pr, err := app.Parse(
+			[]string{"com"},
+			warg.ParseWithLookupEnv(warg.LookupMap(nil)),
+			warg.ParseWithContext(ctx),
+		)
+		require.Nil(t, err)
+		require.Equal(t, "value", pr.Context.Context.Value(ctxKey{}))
+	})
+}
+
+func TestApp_Parse_hooks(t *testing.T) {
+	newApp := func(calls *[]string, beforeErr error) warg.App {
+		record := func(name string) warg.Action {
+			return func(warg.CmdContext) error {
+				*calls = append(*calls, name)
+				return nil
+			}
+		}
+		wrap := func(name string) warg.Middleware {
+			return func(next warg.Action) warg.Action {
+				return func(cmdCtx warg.CmdContext) error {
+					*calls = append(*calls, name+" start")
+					err := next(cmdCtx)
+					*calls = append(*calls, name+" end")
+					return err
+				}
+			}
+		}
+		after := func(name string) warg.AfterFunc {
+			return func(cmdCtx warg.CmdContext, actionErr error) error {
+				*calls = append(*calls, name)
+				if actionErr != nil {
+					return errors.New(name + ": " + actionErr.Error())
+				}
+				return nil
+			}
+		}
+		return warg.New(
+			"newAppName", "v1.0.0",
+			warg.NewSection(
+				"help for test",
+				warg.NewSubSection(
+					"db",
+					"database commands",
+					warg.SectionBefore(record("db before")),
+					warg.SectionAfter(after("db after")),
+					warg.NewSubSection(
+						"schema",
+						"schema commands",
+						warg.SectionWrap(wrap("schema wrap")),
+						warg.NewSubCmd(
+							"migrate",
+							"migrate",
+							func(cmdCtx warg.CmdContext) error {
+								*calls = append(*calls, "migrate")
+								return errors.New("migrate failed")
+							},
+							warg.CmdBefore(func(warg.CmdContext) error {
+								*calls = append(*calls, "migrate before")
+								return beforeErr
+							}),
+						),
+					),
+				),
+				warg.NewSubCmd("status", "status", record("status")),
+			),
+			warg.Wrap(wrap("app wrap")),
+			warg.Before(record("app before")),
+			warg.SkipAll(),
+		)
+	}
+
+	tests := []struct {
+		name          string
+		args          []string
+		beforeErr     error
+		expectedCalls []string
+		expectedErr   string
+	}{
+		{
+			name:      "inheritedInOrder",
+			args:      []string{"db", "schema", "migrate"},
+			beforeErr: nil,
+			expectedCalls: []string{
+				"app wrap start",
+				"app before",
+				"db before",
+				"schema wrap start",
+				"migrate before",
+				"migrate",
+				"schema wrap end",
+				"db after",
+				"app wrap end",
+			},
+			expectedErr: "db after: migrate failed",
+		},
+		{
+			name:      "beforeErrorSkipsAction",
+			args:      []string{"db", "schema", "migrate"},
+			beforeErr: errors.New("not authorized"),
+			expectedCalls: []string{
+				"app wrap start",
+				"app before",
+				"db before",
+				"schema wrap start",
+				"migrate before",
+				"schema wrap end",
+				"db after",
+				"app wrap end",
+			},
+			expectedErr: "db after: not authorized",
+		},
+		{
+			name:      "onlyAppHooksOutsideSection",
+			args:      []string{"status"},
+			beforeErr: nil,
+			expectedCalls: []string{
+				"app wrap start",
+				"app before",
+				"status",
+				"app wrap end",
+			},
+			expectedErr: "",
+		},
+		{
+			name:          "notRunForHelp",
+			args:          []string{"db", "schema", "migrate", "--help", "outline"},
+			beforeErr:     nil,
+			expectedCalls: nil,
+			expectedErr:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			app := newApp(&calls, tt.beforeErr)
+			err := app.Validate()
+			require.Nil(t, err)
+
+			stdout, err := os.CreateTemp(t.TempDir(), "stdout")
+			require.Nil(t, err)
+			defer stdout.Close()
+
+			pr, err := app.Parse(
+				tt.args,
+				warg.ParseWithLookupEnv(warg.LookupMap(nil)),
+				warg.ParseWithStdout(stdout),
+			)
+			require.Nil(t, err)
+
+			err = pr.Action(pr.Context)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+			} else {
+				require.Nil(t, err)
+			}
+			require.Equal(t, tt.expectedCalls, calls)
+		})
+	}
+}
+
+func TestApp_Parse_hooksSkipBuiltinCmds(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "version", args: []string{"version"}},
+		{name: "completion", args: []string{"completion", "bash"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			app := warg.New(
+				"newAppName", "v1.0.0",
+				warg.NewSection(
+					"help for test",
+					warg.NewSubCmd("status", "status", warg.Unimplemented()),
+				),
+				warg.Before(func(warg.CmdContext) error {
+					calls = append(calls, "app before")
+					return errors.New("not authorized")
+				}),
+				warg.Wrap(func(next warg.Action) warg.Action {
+					calls = append(calls, "app wrap")
+					return next
+				}),
+			)
+
+			stdout, err := os.CreateTemp(t.TempDir(), "stdout")
+			require.Nil(t, err)
+			defer stdout.Close()
+
+			pr, err := app.Parse(
+				tt.args,
+				warg.ParseWithLookupEnv(warg.LookupMap(nil)),
+				warg.ParseWithStdout(stdout),
+			)
+			require.Nil(t, err)
+
+			err = pr.Action(pr.Context)
+			require.Nil(t, err)
+			require.Nil(t, calls)
+		})
+	}
+}
+
+func TestApp_Parse_sectionFlags(t *testing.T) {
+	app := warg.New(
+		"newAppName", "v1.0.0",
+		warg.NewSection(
+			"help for test",
+			warg.NewSectionFlag("--context", "context", scalar.String(scalar.Default("default-context"))),
+			warg.NewSubSection(
+				"k8s",
+				"kubernetes commands",
+				warg.NewSectionFlag(
+					"--cluster",
+					"cluster",
+					scalar.String(),
+					warg.Required(),
+					warg.ConfigPath("k8s.cluster"),
+				),
+				warg.NewSubSection(
+					"pods",
+					"pod commands",
+					warg.NewSectionFlag("--namespace", "namespace", scalar.String(), warg.Alias("-n")),
+					warg.NewSubCmd(
+						"get",
+						"get pods",
+						warg.Unimplemented(),
+						warg.NewCmdFlag("--watch", "watch", scalar.Bool()),
+					),
+				),
+			),
+			warg.NewSubCmd("version2", "version", warg.Unimplemented()),
+		),
+		warg.EnvPrefix("MYAPP"),
+		warg.SkipAll(),
+	)
+
+	tests := []struct {
+		name                string
+		args                []string
+		lookup              map[string]string
+		expectedPassedFlags warg.PassedFlags
+		expectedErr         bool
+	}{
+		{
+			name:   "inheritedFromEachSection",
+			args:   []string{"k8s", "pods", "get", "--cluster", "prod", "-n", "web", "--watch", "true", "--context", "mine"},
+			lookup: nil,
+			expectedPassedFlags: warg.PassedFlags{
+				"--cluster":   "prod",
+				"--context":   "mine",
+				"--help":      "default",
+				"--namespace": "web",
+				"--watch":     true,
+			},
+			expectedErr: false,
+		},
+		{
+			name:   "envVarDerivedFromSectionPath",
+			args:   []string{"k8s", "pods", "get"},
+			lookup: map[string]string{"MYAPP_K8S_CLUSTER": "from-env"},
+			expectedPassedFlags: warg.PassedFlags{
+				"--cluster": "from-env",
+				"--context": "default-context",
+				"--help":    "default",
+			},
+			expectedErr: false,
+		},
+		{
+			name:                "requiredSectionFlagMissing",
+			args:                []string{"k8s", "pods", "get"},
+			lookup:              nil,
+			expectedPassedFlags: nil,
+			expectedErr:         true,
+		},
+		{
+			name:                "notInheritedBySiblings",
+			args:                []string{"version2", "--cluster", "prod"},
+			lookup:              nil,
+			expectedPassedFlags: nil,
+			expectedErr:         true,
+		},
+		{
+			name:   "rootSectionFlag",
+			args:   []string{"version2"},
+			lookup: nil,
+			expectedPassedFlags: warg.PassedFlags{
+				"--context": "default-context",
+				"--help":    "default",
+			},
+			expectedErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := app.Validate()
+			require.Nil(t, err)
+
+			actualPR, err := app.Parse(tt.args, warg.ParseWithLookupEnv(warg.LookupMap(tt.lookup)))
+			if tt.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.expectedPassedFlags, actualPR.Context.Flags)
+		})
+	}
+}
+
 // This is the same as TestApp_Parse, but that's too long for a single test
 func TestApp_Parse_GlobalFlag(t *testing.T) {
 	tests := []struct {
@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.bbkane.com/warg"
 	"go.bbkane.com/warg/value/scalar"
+	"go.bbkane.com/warg/value/slice"
 )
 
 func TestApp_Validate(t *testing.T) {
@@ -205,6 +206,433 @@ func TestApp_Validate(t *testing.T) {
 			),
 			expectedErr: true,
 		},
+		{
+			name: "flagNameWithEquals",
+			app: warg.New("newAppName", "v1.0.0",
+				warg.NewSection("",
+					warg.NewSubCmd("com", "", warg.Unimplemented(),
+						warg.NewCmdFlag("--a=b", "", scalar.String()),
+					),
+				),
+				warg.SkipValidation(),
+			),
+			expectedErr: true,
+		},
+		{
+			name: "switchNotBool",
+			app: warg.New("newAppName", "v1.0.0",
+				warg.NewSection("",
+					warg.NewSubCmd("com", "", warg.Unimplemented(),
+						warg.NewCmdFlag("--name", "", scalar.String(), warg.Switch()),
+					),
+				),
+				warg.SkipValidation(),
+			),
+			expectedErr: true,
+		},
+		{
+			name: "negationNameConflict",
+			app: warg.New("newAppName", "v1.0.0",
+				warg.NewSection("",
+					warg.NewSubCmd("com", "", warg.Unimplemented(),
+						warg.NewCmdFlag("--color", "", scalar.Bool(), warg.Negatable()),
+						warg.NewCmdFlag("--no-color", "", scalar.Bool()),
+					),
+				),
+				warg.SkipValidation(),
+			),
+			expectedErr: true,
+		},
+		{
+			name: "positionalRequiredAfterOptional",
+			app: warg.New("newAppName", "v1.0.0",
+				warg.NewSection("",
+					warg.NewSubCmd("com", "", warg.Unimplemented(),
+						warg.CmdPositional("SRC", "", scalar.String()),
+						warg.CmdPositional("DST", "", scalar.String(), warg.PositionalRequired()),
+					),
+				),
+				warg.SkipValidation(),
+			),
+			expectedErr: true,
+		},
+		{
+			name: "positionalVariadicNotLast",
+			app: warg.New("newAppName", "v1.0.0",
+				warg.NewSection("",
+					warg.NewSubCmd("com", "", warg.Unimplemented(),
+						warg.CmdPositional("SRCS", "", slice.String(), warg.PositionalVariadic()),
+						warg.CmdPositional("DST", "", scalar.String()),
+					),
+				),
+				warg.SkipValidation(),
+			),
+			expectedErr: true,
+		},
+		{
+			name: "positionalVariadicNotSlice",
+			app: warg.New("newAppName", "v1.0.0",
+				warg.NewSection("",
+					warg.NewSubCmd("com", "", warg.Unimplemented(),
+						warg.CmdPositional("SRCS", "", scalar.String(), warg.PositionalVariadic()),
+					),
+				),
+				warg.SkipValidation(),
+			),
+			expectedErr: true,
+		},
+		{
+			name: "positionalNameConflict",
+			app: warg.New("newAppName", "v1.0.0",
+				warg.NewSection("",
+					warg.NewSubCmd("com", "", warg.Unimplemented(),
+						warg.CmdPositional("SRC", "", scalar.String()),
+						warg.CmdPositional("SRC", "", scalar.String()),
+					),
+				),
+				warg.SkipValidation(),
+			),
+			expectedErr: true,
+		},
+		{
+			name: "positionalNameWithDash",
+			app: warg.New("newAppName", "v1.0.0",
+				warg.NewSection("",
+					warg.NewSubCmd("com", "", warg.Unimplemented(),
+						warg.CmdPositional("-src", "", scalar.String()),
+					),
+				),
+				warg.SkipValidation(),
+			),
+			expectedErr: true,
+		},
+		{
+			name: "configPathNestedUnderConfigPath",
+			app: warg.New("newAppName", "v1.0.0",
+				warg.NewSection("",
+					warg.NewSubCmd("com", "", warg.Unimplemented(),
+						warg.NewCmdFlag("--db", "", scalar.String(), warg.ConfigPath("db")),
+						warg.NewCmdFlag("--db-url", "", scalar.String(), warg.ConfigPath("db.url")),
+					),
+				),
+				warg.SkipValidation(),
+			),
+			expectedErr: true,
+		},
+		{
+			name: "configPathNestedUnderRenamedConfigPath",
+			app: warg.New("newAppName", "v1.0.0",
+				warg.NewSection("",
+					warg.NewSubCmd("com", "", warg.Unimplemented(),
+						warg.NewCmdFlag("--name", "", scalar.String(), warg.ConfigPath("name"), warg.RenamedFrom("--sub", warg.OldConfigPath("subs"))),
+						warg.NewCmdFlag("--sub-name", "", slice.String(), warg.ConfigPath("subs[].name")),
+					),
+				),
+				warg.SkipValidation(),
+			),
+			expectedErr: true,
+		},
+		{
+			name: "configPathsSiblings",
+			app: warg.New("newAppName", "v1.0.0",
+				warg.NewSection("",
+					warg.NewSubCmd("com", "", warg.Unimplemented(),
+						warg.NewCmdFlag("--db-host", "", scalar.String(), warg.ConfigPath("db.host")),
+						warg.NewCmdFlag("--db-hostname", "", scalar.String(), warg.ConfigPath("db.hostname")),
+					),
+				),
+				warg.SkipValidation(),
+			),
+			expectedErr: false,
+		},
+		{
+			name: "positionalNilValue",
+			app: warg.New("newAppName", "v1.0.0",
+				warg.NewSection("",
+					warg.NewSubCmd("com", "", warg.Unimplemented(),
+						warg.CmdPositional("SRC", "", nil),
+					),
+				),
+				warg.SkipValidation(),
+			),
+			expectedErr: true,
+		},
+		{
+			name: "positionalValid",
+			app: warg.New("newAppName", "v1.0.0",
+				warg.NewSection("",
+					warg.NewSubCmd("com", "", warg.Unimplemented(),
+						warg.CmdPositional("SRC", "", scalar.String(), warg.PositionalRequired()),
+						warg.CmdPositional("DSTS", "", slice.String(), warg.PositionalVariadic()),
+					),
+				),
+				warg.SkipValidation(),
+			),
+			expectedErr: false,
+		},
+		{
+			name: "constraintUnknownFlag",
+			app: warg.New("newAppName", "v1.0.0",
+				warg.NewSection("",
+					warg.NewSubCmd("com", "", warg.Unimplemented(),
+						warg.NewCmdFlag("--json", "", scalar.Bool()),
+						warg.MutuallyExclusive("--json", "--table"),
+					),
+				),
+				warg.SkipValidation(),
+			),
+			expectedErr: true,
+		},
+		{
+			name: "constraintTooFewFlags",
+			app: warg.New("newAppName", "v1.0.0",
+				warg.NewSection("",
+					warg.NewSubCmd("com", "", warg.Unimplemented(),
+						warg.NewCmdFlag("--json", "", scalar.Bool()),
+						warg.AtLeastOneOf("--json"),
+					),
+				),
+				warg.SkipValidation(),
+			),
+			expectedErr: true,
+		},
+		{
+			name: "constraintMutuallyExclusiveRequired",
+			app: warg.New("newAppName", "v1.0.0",
+				warg.NewSection("",
+					warg.NewSubCmd("com", "", warg.Unimplemented(),
+						warg.NewCmdFlag("--json", "", scalar.Bool(), warg.Required()),
+						warg.NewCmdFlag("--table", "", scalar.Bool()),
+						warg.MutuallyExclusive("--json", "--table"),
+					),
+				),
+				warg.SkipValidation(),
+			),
+			expectedErr: true,
+		},
+		{
+			name: "constraintValidWithGlobalFlag",
+			app: warg.New("newAppName", "v1.0.0",
+				warg.NewSection("",
+					warg.NewSubCmd("com", "", warg.Unimplemented(),
+						warg.NewCmdFlag("--user", "", scalar.String()),
+						warg.RequiredTogether("--user", "--password"),
+					),
+				),
+				warg.NewGlobalFlag("--password", "", scalar.String()),
+				warg.SkipValidation(),
+			),
+			expectedErr: false,
+		},
+		{
+			name: "ruleUnknownFlag",
+			app: warg.New("newAppName", "v1.0.0",
+				warg.NewSection("",
+					warg.NewSubCmd("com", "", warg.Unimplemented(),
+						warg.NewCmdFlag("--cert", "", scalar.String()),
+						warg.DependsOn("--cert", "--key"),
+					),
+				),
+				warg.SkipValidation(),
+			),
+			expectedErr: true,
+		},
+		{
+			name: "ruleValueTypeMismatch",
+			app: warg.New("newAppName", "v1.0.0",
+				warg.NewSection("",
+					warg.NewSubCmd("com", "", warg.Unimplemented(),
+						warg.NewCmdFlag("--cert", "", scalar.String()),
+						warg.NewCmdFlag("--port", "", scalar.Int()),
+						warg.RequiredIf("--cert", "--port", "443"),
+					),
+				),
+				warg.SkipValidation(),
+			),
+			expectedErr: true,
+		},
+		{
+			name: "ruleValid",
+			app: warg.New("newAppName", "v1.0.0",
+				warg.NewSection("",
+					warg.NewSubCmd("com", "", warg.Unimplemented(),
+						warg.NewCmdFlag("--cert", "", scalar.String()),
+						warg.NewCmdFlag("--port", "", scalar.Int()),
+						warg.RequiredIf("--cert", "--port", 443),
+					),
+				),
+				warg.SkipValidation(),
+			),
+			expectedErr: false,
+		},
+		{
+			name: "deprecationRemovalPast",
+			app: warg.New("newAppName", "v2.0.0",
+				warg.NewSection("",
+					warg.NewSubCmd("com", "", warg.Unimplemented(),
+						warg.NewCmdFlag("--old", "", scalar.String(), warg.FlagDeprecated("use --new", warg.RemovedIn("v2.0.0"))),
+					),
+				),
+				warg.ValidateDeprecationRemovals(),
+				warg.SkipValidation(),
+			),
+			expectedErr: true,
+		},
+		{
+			name: "deprecationRemovalFuture",
+			app: warg.New("newAppName", "v1.9.0",
+				warg.NewSection("",
+					warg.NewSubCmd("com", "", warg.Unimplemented(),
+						warg.CmdDeprecated("use other", warg.RemovedIn("v2.0.0")),
+					),
+				),
+				warg.ValidateDeprecationRemovals(),
+				warg.SkipValidation(),
+			),
+			expectedErr: false,
+		},
+		{
+			name: "deprecationRemovalNotValidated",
+			app: warg.New("newAppName", "v3.0.0",
+				warg.NewSection("",
+					warg.NewSubSection("old", "", warg.SectionDeprecated("use new", warg.RemovedIn("v2.0.0")),
+						warg.NewSubCmd("com", "", warg.Unimplemented()),
+					),
+				),
+				warg.SkipValidation(),
+			),
+			expectedErr: false,
+		},
+		{
+			name: "cmdAliasClashesWithSection",
+			app: warg.New("newAppName", "v1.0.0",
+				warg.NewSection("",
+					warg.NewSubCmd("remove", "", warg.Unimplemented(), warg.CmdAliases("rm")),
+					warg.NewSubSection("rm", "",
+						warg.NewSubCmd("com", "", warg.Unimplemented()),
+					),
+				),
+				warg.SkipValidation(),
+			),
+			expectedErr: true,
+		},
+		{
+			name: "sectionAliasClashesWithCmdAlias",
+			app: warg.New("newAppName", "v1.0.0",
+				warg.NewSection("",
+					warg.NewSubCmd("remove", "", warg.Unimplemented(), warg.CmdAliases("x")),
+					warg.NewSubSection("extra", "", warg.SectionAliases("x"),
+						warg.NewSubCmd("com", "", warg.Unimplemented()),
+					),
+				),
+				warg.SkipValidation(),
+			),
+			expectedErr: true,
+		},
+		{
+			name: "flagAliasesClash",
+			app: warg.New("newAppName", "v1.0.0",
+				warg.NewSection("",
+					warg.NewSubCmd("com", "", warg.Unimplemented(),
+						warg.NewCmdFlag("--force", "", scalar.Bool(), warg.Aliases("-f", "-y")),
+						warg.NewCmdFlag("--yes", "", scalar.Bool(), warg.Alias("-y")),
+					),
+				),
+				warg.SkipValidation(),
+			),
+			expectedErr: true,
+		},
+		{
+			name: "renamedFromClashesWithFlag",
+			app: warg.New("newAppName", "v1.0.0",
+				warg.NewSection("",
+					warg.NewSubCmd("com", "", warg.Unimplemented(),
+						warg.NewCmdFlag("--database", "", scalar.String(), warg.RenamedFrom("--db")),
+						warg.NewCmdFlag("--db", "", scalar.String()),
+					),
+				),
+				warg.SkipValidation(),
+			),
+			expectedErr: true,
+		},
+		{
+			name: "sectionFlagClashesWithCmdFlag",
+			app: warg.New("newAppName", "v1.0.0",
+				warg.NewSection("",
+					warg.NewSubSection("k8s", "",
+						warg.NewSectionFlag("--cluster", "", scalar.String()),
+						warg.NewSubCmd("get", "", warg.Unimplemented(),
+							warg.NewCmdFlag("--cluster", "", scalar.String()),
+						),
+					),
+				),
+				warg.SkipValidation(),
+			),
+			expectedErr: true,
+		},
+		{
+			name: "sectionFlagClashesWithAncestorSectionFlagAlias",
+			app: warg.New("newAppName", "v1.0.0",
+				warg.NewSection("",
+					warg.NewSectionFlag("--context", "", scalar.String(), warg.Alias("-c")),
+					warg.NewSubSection("k8s", "",
+						warg.NewSectionFlag("--cluster", "", scalar.String(), warg.Alias("-c")),
+						warg.NewSubCmd("get", "", warg.Unimplemented()),
+					),
+				),
+				warg.SkipValidation(),
+			),
+			expectedErr: true,
+		},
+		{
+			name: "sectionFlagClashesWithGlobalFlag",
+			app: warg.New("newAppName", "v1.0.0",
+				warg.NewSection("",
+					warg.NewSubSection("k8s", "",
+						warg.NewSectionFlag("--help", "", scalar.String()),
+						warg.NewSubCmd("get", "", warg.Unimplemented()),
+					),
+				),
+				warg.SkipValidation(),
+			),
+			expectedErr: true,
+		},
+		{
+			name: "sectionFlagsInSiblingSectionsValid",
+			app: warg.New("newAppName", "v1.0.0",
+				warg.NewSection("",
+					warg.NewSubSection("k8s", "",
+						warg.NewSectionFlag("--cluster", "", scalar.String()),
+						warg.NewSubCmd("get", "", warg.Unimplemented(),
+							warg.MutuallyExclusive("--cluster", "--all"),
+							warg.NewCmdFlag("--all", "", scalar.Bool()),
+						),
+					),
+					warg.NewSubSection("nomad", "",
+						warg.NewSectionFlag("--cluster", "", scalar.String()),
+						warg.NewSubCmd("get", "", warg.Unimplemented()),
+					),
+				),
+				warg.SkipValidation(),
+			),
+			expectedErr: false,
+		},
+		{
+			name: "aliasesValid",
+			app: warg.New("newAppName", "v1.0.0",
+				warg.NewSection("",
+					warg.NewSubCmd("remove", "", warg.Unimplemented(),
+						warg.CmdAliases("rm"),
+						warg.NewCmdFlag("--database", "", scalar.String(), warg.RenamedFrom("--db"), warg.Aliases("-d", "--data")),
+					),
+					warg.NewSubSection("extra", "", warg.SectionAliases("x"),
+						warg.NewSubCmd("com", "", warg.Unimplemented()),
+					),
+				),
+				warg.SkipValidation(),
+			),
+			expectedErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"strings"
 
 	"go.bbkane.com/warg/styles"
@@ -71,6 +72,9 @@ func (w Wrapped) ColorError(s *styles.Styles) string {
 }
 
 func (w Wrapped) Error() string {
+	if w.err == nil {
+		return w.msg
+	}
 	return w.msg + ": " + w.err.Error()
 }
 
@@ -101,6 +105,9 @@ func (w Wrappedf) Error() string {
 	for _, a := range w.args {
 		args = append(args, a)
 	}
+	if w.err == nil {
+		return fmt.Sprintf(w.msg, args...)
+	}
 	return fmt.Sprintf(w.msg, args...) + ": " + w.err.Error()
 }
 
@@ -143,16 +150,77 @@ func (e ArgChoiceError) Error() string {
 	return fmt.Sprintf("%s, got %s. Choices: %v", e.Message, e.Arg, e.Choices)
 }
 
+// ColorError lists the closest choices as "Did you mean" suggestions if any are close to Arg,
+// and otherwise lists every choice.
 func (e ArgChoiceError) ColorError(s *styles.Styles) string {
 	var buf strings.Builder
 	buf.WriteString(e.Message + "\n")
 	buf.WriteString("Got: " + string(s.ErrorAltCode) + e.Arg + string(s.ErrorCode) + "\n")
-	buf.WriteString("Choices:\n")
+	choices := ClosestChoices(e.Arg, e.Choices, maxSuggestions)
+	if len(choices) > 0 {
+		buf.WriteString("Did you mean:\n")
+	} else {
+		buf.WriteString("Choices:\n")
+		choices = e.Choices
+	}
 	buf.WriteString(string(s.ErrorAltCode))
-	for _, c := range e.Choices {
+	for _, c := range choices {
 		buf.WriteString("  " + c + "\n")
 	}
 	buf.WriteString(string(s.ErrorCode))
 
 	return s.Error(buf.String())
 }
+
+// maxSuggestions is the most typo suggestions [ArgChoiceError] shows. Choices Arg is a prefix of are always shown.
+const maxSuggestions = 2
+
+// ClosestChoices returns the choices that look like typos of arg, closest first. Every choice starting
+// with arg comes first (arg may be an ambiguous prefix), so none are hidden. Then come up to n choices
+// within a Levenshtein distance of a third of arg's length (at least 1). Ties keep the order of choices.
+func ClosestChoices(arg string, choices []string, n int) []string {
+	type scored struct {
+		choice string
+		score  int
+	}
+	maxDistance := max(len(arg)/3, 1)
+	var prefixed []string
+	var typos []scored
+	for _, c := range choices {
+		if arg != "" && strings.HasPrefix(c, arg) {
+			prefixed = append(prefixed, c)
+		} else if d := levenshtein(arg, c); d <= maxDistance {
+			typos = append(typos, scored{choice: c, score: d})
+		}
+	}
+	slices.SortStableFunc(typos, func(a scored, b scored) int {
+		return a.score - b.score
+	})
+	ret := make([]string, 0, len(prefixed)+min(n, len(typos)))
+	ret = append(ret, prefixed...)
+	for i := 0; i < len(typos) && i < n; i++ {
+		ret = append(ret, typos[i].choice)
+	}
+	return ret
+}
+
+// levenshtein returns the number of single-byte insertions, deletions, and substitutions needed to turn a into b.
+func levenshtein(a string, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	"go.bbkane.com/warg/styles"
@@ -100,6 +101,28 @@ func TestArgChoiceError(t *testing.T) {
 				"Parse args error",
 			),
 		},
+		{
+			name: "did_you_mean",
+			err: NewWrapped(
+				ArgChoiceError{
+					Message: "expecting section or command",
+					Arg:     "staus",
+					Choices: []string{"config", "start", "status", "stop"},
+				},
+				"Parse args error",
+			),
+		},
+		{
+			name: "ambiguous_prefix",
+			err: NewWrapped(
+				ArgChoiceError{
+					Message: "expecting section or command",
+					Arg:     "st",
+					Choices: []string{"config", "start", "status", "stop"},
+				},
+				"Parse args error",
+			),
+		},
 	}
 
 	for _, tt := range tests {
@@ -138,3 +161,67 @@ func TestArgChoiceError(t *testing.T) {
 		})
 	})
 }
+
+func TestClosestChoices(t *testing.T) {
+	tests := []struct {
+		name     string
+		arg      string
+		choices  []string
+		expected []string
+	}{
+		{
+			name:     "typo",
+			arg:      "--colr",
+			choices:  []string{"--color", "--help", "--name"},
+			expected: []string{"--color"},
+		},
+		{
+			name:     "closestFirst",
+			arg:      "stat",
+			choices:  []string{"start", "status", "stop"},
+			expected: []string{"status", "start"},
+		},
+		{
+			name:     "prefixesBeforeTypos",
+			arg:      "sto",
+			choices:  []string{"stat", "stop", "storage"},
+			expected: []string{"stop", "storage"},
+		},
+		{
+			name:     "allPrefixMatches",
+			arg:      "st",
+			choices:  []string{"start", "status", "stop"},
+			expected: []string{"start", "status", "stop"},
+		},
+		{
+			name:     "typosCapped",
+			arg:      "stap",
+			choices:  []string{"slap", "snap", "stab", "step"},
+			expected: []string{"slap", "snap"},
+		},
+		{
+			name:     "nothingClose",
+			arg:      "bogus",
+			choices:  []string{"config", "create", "delete"},
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := ClosestChoices(tt.arg, tt.choices, 2)
+			if !slices.Equal(tt.expected, actual) {
+				t.Fatalf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestError_nilWrapped(t *testing.T) {
+	if got := NewWrapped(nil, "root cause").Error(); got != "root cause" {
+		t.Fatalf("unexpected error message: %q", got)
+	}
+	if got := NewWrappedf(nil, "root cause: %s", "arg").Error(); got != "root cause: arg" {
+		t.Fatalf("unexpected error message: %q", got)
+	}
+}
@@ -1,6 +1,7 @@
 package warg
 
 import (
+	"context"
 	"errors"
 	"os"
 	"sort"
@@ -24,12 +25,23 @@ func Unimplemented() Action {
 // Use [NewSubCmd] to simultaneously create and attach a command to a [Section].
 func NewCmd(helpShort string, action Action, opts ...CmdOpt) Cmd {
 	command := Cmd{
-		HelpShort:          helpShort,
-		Action:             action,
-		Flags:              make(FlagMap),
-		AllowForwardedArgs: false,
-		Footer:             "",
-		HelpLong:           "",
+		Aliases:                  nil,
+		HelpShort:                helpShort,
+		Action:                   action,
+		Flags:                    make(FlagMap),
+		Positionals:              nil,
+		AllowForwardedArgs:       false,
+		Constraints:              nil,
+		Deprecation:              nil,
+		Rules:                    nil,
+		Validators:               nil,
+		Footer:                   "",
+		HandlesSignals:           false,
+		HelpLong:                 "",
+		Hidden:                   false,
+		Middlewares:              nil,
+		SkipConfig:               false,
+		SkipInheritedMiddlewares: false,
 	}
 	for _, opt := range opts {
 		opt(&command)
@@ -56,6 +68,19 @@ func NewCmdFlag(name string, helpShort string, empty value.EmptyConstructor, opt
 	return CmdFlag(name, NewFlag(helpShort, empty, opts...))
 }
 
+// CmdPositional creates a new [Positional] and appends it to the command's positional arguments.
+// Positionals are filled in the order they are added. Name collisions and ordering rules
+// (required before optional, only the last may be variadic) are checked by [App.Validate].
+//
+// Example usage:
+//
+//	butler copy SRC DST
+func CmdPositional(name string, helpShort string, empty value.EmptyConstructor, opts ...PositionalOpt) CmdOpt {
+	return func(com *Cmd) {
+		com.Positionals = append(com.Positionals, NewPositional(name, helpShort, empty, opts...))
+	}
+}
+
 // CmdFooter sets an optional footer text displayed at the end of help output for this command.
 func CmdFooter(footer string) CmdOpt {
 	return func(cat *Cmd) {
@@ -82,6 +107,25 @@ func AllowForwardedArgs() CmdOpt {
 	}
 }
 
+// CmdSkipConfig stops config files (from [ConfigFile] and the config flag) from being read when this command
+// is parsed, so flags are resolved only from the command line, env vars, and defaults. The config flag itself is
+// still resolved. Useful for commands that inspect config files themselves and shouldn't fail when they're invalid.
+func CmdSkipConfig() CmdOpt {
+	return func(cmd *Cmd) {
+		cmd.SkipConfig = true
+	}
+}
+
+// CmdSkipInheritedMiddlewares stops app and section middlewares (from [Wrap], [Before], [After], and the
+// Section variants) from running around this command. Its own [CmdWrap] middlewares still run. The built-in
+// commands (version, completion, repl, config, man, and docs) use it so hooks such as auth checks don't run
+// for them. Commands run inside the REPL still run their hooks.
+func CmdSkipInheritedMiddlewares() CmdOpt {
+	return func(cmd *Cmd) {
+		cmd.SkipInheritedMiddlewares = true
+	}
+}
+
 // PassedFlags is a map of flag names to their resolved values, containing only flags
 // that were set from any source (CLI, config, env var, or default).
 // TODO: is this true?
@@ -90,10 +134,19 @@ type PassedFlags map[string]interface{} // This can just stay a string for the c
 // CmdContext holds all parsed information passed to an [Action] when a command is executed.
 // It includes resolved flags, forwarded args, I/O streams, and parse metadata.
 type CmdContext struct {
-	App           *App
+	App *App
+
+	// Context is cancelled when [App.MustRun] receives SIGINT or SIGTERM. Long-running actions
+	// should pass it to anything that blocks and return promptly once it's done.
+	// See [SignalGracePeriod] and [ParseWithContext].
+	Context context.Context
+
 	Flags         PassedFlags
 	ForwardedArgs []string
 
+	// Positionals holds set positional argument values keyed by [Positional].Name
+	Positionals PassedFlags
+
 	ParseState *ParseState
 
 	// ParseMetadata to smuggle user-defined state (i.e., not flags) into an Action. I use this for mocks when testing
@@ -128,26 +181,71 @@ func (fm CmdMap) SortedNames() []string {
 	return keys
 }
 
+// visibleSortedNames returns the names of commands that aren't hidden, in alphabetical order.
+func (fm CmdMap) visibleSortedNames() []string {
+	names := []string{}
+	for _, name := range fm.SortedNames() {
+		if !fm[name].Hidden {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
 // Cmd represents an executable command within the CLI.
 // Command names should be verbs (e.g., "add", "edit", "run").
 // Do not construct directly; use [NewCmd] or [NewSubCmd].
 type Cmd struct {
+	// Aliases are alternative names for the command (see [CmdAliases])
+	Aliases []string
+
 	// Action to run when command is invoked
 	Action Action
 
 	// Parsed Flags
 	Flags FlagMap
 
+	// Positionals are the ordered positional arguments this command accepts
+	Positionals PositionalList
+
 	// AllowForwardedArgs indicates whether or not extra args are allowed after flags and following `--`.
 	// These args will be accessible in CmdContext.ForwardedArgs.
 	AllowForwardedArgs bool
 
+	// Constraints are cross-flag relationships (see [MutuallyExclusive], [RequiredTogether], [AtLeastOneOf])
+	// checked after flag values are resolved.
+	Constraints []FlagConstraint
+
+	// Rules are conditional flag relationships (see [RequiredIf], [DependsOn]) checked after flag values are resolved.
+	Rules []FlagRule
+
+	// Validators are run after flags and positionals are resolved (see [CmdValidator]).
+	Validators []CmdValidatorFunc
+
+	// Deprecation marks this command as deprecated (see [CmdDeprecated])
+	Deprecation *Deprecation
+
 	// Footer is yet another optional longer description.
 	Footer string
 
+	// HandlesSignals stops [App.MustRun] from cancelling the command's context on SIGINT or SIGTERM (see [CmdHandlesSignals])
+	HandlesSignals bool
+
 	// HelpLong is an optional longer description
 	HelpLong string
 
 	// HelpShort is a required one-line description
 	HelpShort string
+
+	// Hidden omits this command from help and completions (see [CmdHidden])
+	Hidden bool
+
+	// Middlewares wrap Action, inside any inherited from sections (see [CmdWrap])
+	Middlewares []Middleware
+
+	// SkipConfig stops config files from being read when this command is parsed (see [CmdSkipConfig])
+	SkipConfig bool
+
+	// SkipInheritedMiddlewares stops app and section middlewares from wrapping Action (see [CmdSkipInheritedMiddlewares])
+	SkipInheritedMiddlewares bool
 }
@@ -0,0 +1,410 @@
+// Package compat compares two versions of a warg app's command line interface, as described by
+// [warg.App.Describe], and reports breaking and additive changes. Use [RequireCompatible] in a test
+// to fail CI when a change would break existing invocations, scripts, env vars, or config files.
+package compat
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.bbkane.com/warg"
+	"go.bbkane.com/warg/colerr"
+)
+
+// ChangeKind classifies a [Change].
+type ChangeKind string
+
+const (
+	// ChangeBreaking means invocations, env vars, or config files that worked with the old version might fail with the new one.
+	ChangeBreaking ChangeKind = "breaking"
+	// ChangeAdditive means the new version accepts something the old one didn't.
+	ChangeAdditive ChangeKind = "additive"
+)
+
+// Change is one difference between two versions of an app.
+type Change struct {
+	Kind ChangeKind `json:"kind"`
+	// Path is the app name and the path to what changed, such as "myapp k8s get --cluster".
+	// Flags declared by a section or globally are reported once, at the section or app.
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// String formats the change like "breaking: myapp k8s get --cluster: flag is now required".
+func (c Change) String() string {
+	return string(c.Kind) + ": " + c.Path + ": " + c.Message
+}
+
+// Report holds the changes from an old version of an app to a new one, breaking changes first.
+type Report struct {
+	Changes []Change `json:"changes"`
+}
+
+// Breaking returns the breaking changes.
+func (r Report) Breaking() []Change {
+	return r.ofKind(ChangeBreaking)
+}
+
+// Additive returns the additive changes.
+func (r Report) Additive() []Change {
+	return r.ofKind(ChangeAdditive)
+}
+
+func (r Report) ofKind(kind ChangeKind) []Change {
+	var ret []Change
+	for _, c := range r.Changes {
+		if c.Kind == kind {
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+// CompareJSON unmarshals two JSON descriptions (from "--help json" or [warg.App.Describe]) and compares them with [Compare].
+func CompareJSON(oldJSON []byte, newJSON []byte) (Report, error) {
+	var oldDesc warg.AppDescription
+	err := json.Unmarshal(oldJSON, &oldDesc)
+	if err != nil {
+		return Report{Changes: nil}, colerr.NewWrapped(err, "Could not unmarshal old description")
+	}
+	var newDesc warg.AppDescription
+	err = json.Unmarshal(newJSON, &newDesc)
+	if err != nil {
+		return Report{Changes: nil}, colerr.NewWrapped(err, "Could not unmarshal new description")
+	}
+	return Compare(oldDesc, newDesc)
+}
+
+// Compare reports what changed from oldDesc to newDesc. Renamed flags (see [warg.RenamedFrom]) and
+// commands or sections still reachable by an alias aren't reported as removed. Help text, defaults,
+// and deprecations aren't compared. Returns an error if either description has a schema version
+// newer than [warg.DescriptionSchemaVersion].
+func Compare(oldDesc warg.AppDescription, newDesc warg.AppDescription) (Report, error) {
+	for _, desc := range []warg.AppDescription{oldDesc, newDesc} {
+		if desc.SchemaVersion > warg.DescriptionSchemaVersion {
+			return Report{Changes: nil}, colerr.NewWrappedf(
+				nil,
+				"Unsupported description schema version %s for %s. The newest supported version is %s",
+				strconv.Itoa(desc.SchemaVersion),
+				desc.Name,
+				strconv.Itoa(warg.DescriptionSchemaVersion),
+			)
+		}
+	}
+
+	c := comparer{changes: nil}
+	appPath := []string{newDesc.Name}
+	c.compareSection(
+		appPath,
+		oldDesc.RootSection,
+		newDesc.RootSection,
+		scopeFlags(nil, appPath, oldDesc.GlobalFlags),
+		scopeFlags(nil, appPath, newDesc.GlobalFlags),
+	)
+
+	// breaking changes first, otherwise in the order found
+	slices.SortStableFunc(c.changes, func(a Change, b Change) int {
+		return kindOrder(a.Kind) - kindOrder(b.Kind)
+	})
+	return Report{Changes: c.changes}, nil
+}
+
+func kindOrder(kind ChangeKind) int {
+	if kind == ChangeBreaking {
+		return 0
+	}
+	return 1
+}
+
+// scopedFlag is a flag along with the path of the section, command, or app declaring it.
+type scopedFlag struct {
+	path []string
+	flag warg.FlagDescription
+}
+
+// accepts reports whether the flag can be passed as name.
+func (sf scopedFlag) accepts(name string) bool {
+	return sf.flag.Name == name || slices.Contains(sf.flag.Aliases, name) || slices.Contains(sf.flag.RenamedFrom, name)
+}
+
+// scopeFlags appends flags declared at path to inherited.
+func scopeFlags(inherited []scopedFlag, path []string, flags []warg.FlagDescription) []scopedFlag {
+	ret := slices.Clone(inherited)
+	for _, fl := range flags {
+		ret = append(ret, scopedFlag{path: path, flag: fl})
+	}
+	return ret
+}
+
+func findFlag(flags []scopedFlag, name string) (scopedFlag, bool) {
+	for _, sf := range flags {
+		if sf.accepts(name) {
+			return sf, true
+		}
+	}
+	var zero scopedFlag
+	return zero, false
+}
+
+type comparer struct {
+	changes []Change
+}
+
+// add records a change, skipping duplicates. Inherited flags are compared for every command, so the
+// same change can be found more than once.
+func (c *comparer) add(kind ChangeKind, path []string, message string) {
+	change := Change{Kind: kind, Path: strings.Join(path, " "), Message: message}
+	if !slices.Contains(c.changes, change) {
+		c.changes = append(c.changes, change)
+	}
+}
+
+// childPath returns a copy of path with name appended.
+func childPath(path []string, name string) []string {
+	return append(slices.Clone(path), name)
+}
+
+// compareNamed compares the names and aliases of old and new children (sections or commands) and calls
+// compare on each old child still reachable by its name.
+func compareNamed[T any](
+	c *comparer,
+	path []string,
+	noun string,
+	oldChildren []T,
+	newChildren []T,
+	names func(T) (string, []string),
+	compare func(childPath []string, oldChild T, newChild T),
+) {
+	find := func(children []T, name string) (T, bool) {
+		for _, child := range children {
+			childName, aliases := names(child)
+			if childName == name || slices.Contains(aliases, name) {
+				return child, true
+			}
+		}
+		var zero T
+		return zero, false
+	}
+
+	for _, oldChild := range oldChildren {
+		name, aliases := names(oldChild)
+		newChild, found := find(newChildren, name)
+		if !found {
+			c.add(ChangeBreaking, childPath(path, name), noun+" removed")
+			continue
+		}
+		for _, alias := range aliases {
+			if _, found := find(newChildren, alias); !found {
+				c.add(ChangeBreaking, childPath(path, name), noun+" alias "+alias+" removed")
+			}
+		}
+		compare(childPath(path, name), oldChild, newChild)
+	}
+	for _, newChild := range newChildren {
+		name, aliases := names(newChild)
+		if _, found := find(oldChildren, name); !found {
+			c.add(ChangeAdditive, childPath(path, name), noun+" added")
+			continue
+		}
+		for _, alias := range aliases {
+			if _, found := find(oldChildren, alias); !found {
+				c.add(ChangeAdditive, childPath(path, name), noun+" alias "+alias+" added")
+			}
+		}
+	}
+}
+
+func (c *comparer) compareSection(path []string, oldSec warg.SectionDescription, newSec warg.SectionDescription, oldFlags []scopedFlag, newFlags []scopedFlag) {
+	// section flags are compared with each command's flags, so moving a flag between a command and its sections isn't a change
+	oldFlags = scopeFlags(oldFlags, path, oldSec.Flags)
+	newFlags = scopeFlags(newFlags, path, newSec.Flags)
+
+	compareNamed(
+		c,
+		path,
+		"command",
+		oldSec.Cmds,
+		newSec.Cmds,
+		func(cmd warg.CmdDescription) (string, []string) { return cmd.Name, cmd.Aliases },
+		func(cmdPath []string, oldCmd warg.CmdDescription, newCmd warg.CmdDescription) {
+			c.compareCmd(cmdPath, oldCmd, newCmd, oldFlags, newFlags)
+		},
+	)
+	compareNamed(
+		c,
+		path,
+		"section",
+		oldSec.Sections,
+		newSec.Sections,
+		func(sec warg.SectionDescription) (string, []string) { return sec.Name, sec.Aliases },
+		func(secPath []string, oldChild warg.SectionDescription, newChild warg.SectionDescription) {
+			c.compareSection(secPath, oldChild, newChild, oldFlags, newFlags)
+		},
+	)
+}
+
+func (c *comparer) compareCmd(path []string, oldCmd warg.CmdDescription, newCmd warg.CmdDescription, oldFlags []scopedFlag, newFlags []scopedFlag) {
+	c.compareFlags(scopeFlags(oldFlags, path, oldCmd.Flags), scopeFlags(newFlags, path, newCmd.Flags))
+
+	for i, oldPos := range oldCmd.Positionals {
+		posPath := childPath(path, oldPos.Name)
+		if i >= len(newCmd.Positionals) {
+			c.add(ChangeBreaking, posPath, "positional removed")
+			continue
+		}
+		newPos := newCmd.Positionals[i]
+		c.compareValues(posPath, oldPos.ValueDescription, newPos.ValueDescription)
+		c.compareBool(posPath, oldPos.Required, newPos.Required, "positional is now required", "positional is no longer required")
+		c.compareBool(posPath, !oldPos.Variadic, !newPos.Variadic, "positional is no longer variadic", "positional is now variadic")
+	}
+	for _, newPos := range newCmd.Positionals[min(len(oldCmd.Positionals), len(newCmd.Positionals)):] {
+		if newPos.Required {
+			c.add(ChangeBreaking, childPath(path, newPos.Name), "required positional added")
+		} else {
+			c.add(ChangeAdditive, childPath(path, newPos.Name), "positional added")
+		}
+	}
+
+	c.compareBool(path, !oldCmd.AllowForwardedArgs, !newCmd.AllowForwardedArgs, "forwarded args are no longer allowed", "forwarded args are now allowed")
+	c.compareRestrictions(path, "constraint", oldCmd.Constraints, newCmd.Constraints)
+}
+
+// compareFlags compares every flag a command accepts, including inherited flags.
+func (c *comparer) compareFlags(oldFlags []scopedFlag, newFlags []scopedFlag) {
+	for _, oldSF := range oldFlags {
+		flagPath := childPath(oldSF.path, oldSF.flag.Name)
+		newSF, found := findFlag(newFlags, oldSF.flag.Name)
+		if !found {
+			c.add(ChangeBreaking, flagPath, "flag removed")
+			continue
+		}
+		for _, alias := range oldSF.flag.Aliases {
+			if _, found := findFlag(newFlags, alias); !found {
+				c.add(ChangeBreaking, flagPath, "flag alias "+alias+" removed")
+			}
+		}
+		c.compareFlag(flagPath, oldSF.flag, newSF.flag)
+	}
+	for _, newSF := range newFlags {
+		flagPath := childPath(newSF.path, newSF.flag.Name)
+		if _, found := findFlag(oldFlags, newSF.flag.Name); !found {
+			if newSF.flag.Required {
+				c.add(ChangeBreaking, flagPath, "required flag added")
+			} else {
+				c.add(ChangeAdditive, flagPath, "flag added")
+			}
+			continue
+		}
+		for _, alias := range newSF.flag.Aliases {
+			if _, found := findFlag(oldFlags, alias); !found {
+				c.add(ChangeAdditive, flagPath, "flag alias "+alias+" added")
+			}
+		}
+	}
+}
+
+func (c *comparer) compareFlag(path []string, oldFlag warg.FlagDescription, newFlag warg.FlagDescription) {
+	c.compareValues(path, oldFlag.ValueDescription, newFlag.ValueDescription)
+	c.compareBool(path, oldFlag.Required, newFlag.Required, "flag is now required", "flag is no longer required")
+	// switches never take a value, so "--flag true" breaks in one direction and "--flag" in the other
+	switch {
+	case oldFlag.Switch && !newFlag.Switch:
+		c.add(ChangeBreaking, path, "flag is no longer a switch")
+	case !oldFlag.Switch && newFlag.Switch:
+		c.add(ChangeBreaking, path, "flag is now a switch")
+	}
+	c.compareBool(path, !oldFlag.Negatable, !newFlag.Negatable, "flag is no longer negatable", "flag is now negatable")
+
+	// env vars and config paths are only breaking when they're no longer read
+	for _, envVar := range oldFlag.EnvVars {
+		if !slices.Contains(newFlag.EnvVars, envVar) {
+			c.add(ChangeBreaking, path, "env var "+envVar+" removed")
+		}
+	}
+	for _, envVar := range newFlag.EnvVars {
+		if !slices.Contains(oldFlag.EnvVars, envVar) {
+			c.add(ChangeAdditive, path, "env var "+envVar+" added")
+		}
+	}
+	oldConfigPaths := configPaths(oldFlag)
+	newConfigPaths := configPaths(newFlag)
+	for _, configPath := range oldConfigPaths {
+		if !slices.Contains(newConfigPaths, configPath) {
+			c.add(ChangeBreaking, path, "config path "+configPath+" removed")
+		}
+	}
+	for _, configPath := range newConfigPaths {
+		if !slices.Contains(oldConfigPaths, configPath) {
+			c.add(ChangeAdditive, path, "config path "+configPath+" added")
+		}
+	}
+}
+
+// configPaths returns every config path a flag is read from.
+func configPaths(fl warg.FlagDescription) []string {
+	var ret []string
+	if fl.ConfigPath != "" {
+		ret = append(ret, fl.ConfigPath)
+	}
+	return append(ret, fl.OldConfigPaths...)
+}
+
+// compareValues compares the values of a flag or positional.
+func (c *comparer) compareValues(path []string, oldVal warg.ValueDescription, newVal warg.ValueDescription) {
+	if oldVal.Type != newVal.Type || oldVal.Kind != newVal.Kind {
+		c.add(ChangeBreaking, path, "type changed from "+oldVal.Kind+" "+oldVal.Type+" to "+newVal.Kind+" "+newVal.Type)
+	}
+
+	switch {
+	case len(oldVal.Choices) == 0 && len(newVal.Choices) > 0:
+		c.add(ChangeBreaking, path, "choices restricted to "+strings.Join(newVal.Choices, ", "))
+	case len(oldVal.Choices) > 0 && len(newVal.Choices) == 0:
+		c.add(ChangeAdditive, path, "choices removed")
+	default:
+		c.compareAccepted(path, "choice", oldVal.Choices, newVal.Choices)
+	}
+
+	c.compareRestrictions(path, "constraint", oldVal.Constraints, newVal.Constraints)
+}
+
+// compareAccepted compares lists of things a flag or command accepts, like choices:
+// removing an element is breaking and adding one is additive.
+func (c *comparer) compareAccepted(path []string, noun string, oldList []string, newList []string) {
+	for _, elem := range oldList {
+		if !slices.Contains(newList, elem) {
+			c.add(ChangeBreaking, path, noun+" "+elem+" removed")
+		}
+	}
+	for _, elem := range newList {
+		if !slices.Contains(oldList, elem) {
+			c.add(ChangeAdditive, path, noun+" "+elem+" added")
+		}
+	}
+}
+
+// compareRestrictions compares lists of restrictions, like constraints:
+// adding an element is breaking and removing one is additive.
+func (c *comparer) compareRestrictions(path []string, noun string, oldList []string, newList []string) {
+	for _, elem := range newList {
+		if !slices.Contains(oldList, elem) {
+			c.add(ChangeBreaking, path, noun+" "+elem+" added")
+		}
+	}
+	for _, elem := range oldList {
+		if !slices.Contains(newList, elem) {
+			c.add(ChangeAdditive, path, noun+" "+elem+" removed")
+		}
+	}
+}
+
+// compareBool reports going from false to true as breaking, and from true to false as additive.
+func (c *comparer) compareBool(path []string, oldVal bool, newVal bool, breakingMessage string, additiveMessage string) {
+	switch {
+	case !oldVal && newVal:
+		c.add(ChangeBreaking, path, breakingMessage)
+	case oldVal && !newVal:
+		c.add(ChangeAdditive, path, additiveMessage)
+	}
+}
@@ -0,0 +1,281 @@
+package compat_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.bbkane.com/warg"
+	"go.bbkane.com/warg/compat"
+	"go.bbkane.com/warg/value/scalar"
+)
+
+func newApp(opts ...warg.SectionOpt) warg.App {
+	return warg.New(
+		"myapp",
+		"v1.0.0",
+		warg.NewSection("Manage my app", opts...),
+		warg.SkipAll(),
+	)
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name     string
+		oldApp   warg.App
+		newApp   warg.App
+		expected []compat.Change
+	}{
+		{
+			name: "noChanges",
+			oldApp: newApp(
+				warg.NewSubCmd("run", "Run", warg.Unimplemented(), warg.NewCmdFlag("--a", "A", scalar.String())),
+			),
+			newApp: newApp(
+				warg.NewSubCmd("run", "Run it", warg.Unimplemented(), warg.NewCmdFlag("--a", "A flag", scalar.String(scalar.Default("x")))),
+			),
+			expected: nil,
+		},
+		{
+			name: "flagRemoved",
+			oldApp: newApp(
+				warg.NewSubCmd("run", "Run", warg.Unimplemented(), warg.NewCmdFlag("--a", "A", scalar.String(), warg.Alias("-a"))),
+			),
+			newApp: newApp(
+				warg.NewSubCmd("run", "Run", warg.Unimplemented()),
+			),
+			expected: []compat.Change{
+				{Kind: compat.ChangeBreaking, Path: "myapp run --a", Message: "flag removed"},
+			},
+		},
+		{
+			name: "flagRenamed",
+			oldApp: newApp(
+				warg.NewSubCmd("run", "Run", warg.Unimplemented(), warg.NewCmdFlag("--a", "A", scalar.String(), warg.ConfigPath("a"))),
+			),
+			newApp: newApp(
+				warg.NewSubCmd(
+					"run",
+					"Run",
+					warg.Unimplemented(),
+					warg.NewCmdFlag("--b", "B", scalar.String(), warg.ConfigPath("b"), warg.RenamedFrom("--a", warg.OldConfigPath("a"))),
+				),
+			),
+			expected: []compat.Change{
+				{Kind: compat.ChangeAdditive, Path: "myapp run --a", Message: "config path b added"},
+				{Kind: compat.ChangeAdditive, Path: "myapp run --b", Message: "flag added"},
+			},
+		},
+		{
+			name: "flagChanged",
+			oldApp: newApp(
+				warg.NewSubCmd(
+					"run",
+					"Run",
+					warg.Unimplemented(),
+					warg.NewCmdFlag("--mode", "Mode", scalar.String(scalar.Choices("a", "b", "c"))),
+					warg.NewCmdFlag("--count", "Count", scalar.String(), warg.Required()),
+					warg.NewCmdFlag("--name", "Name", scalar.String(), warg.EnvVars("NAME")),
+				),
+			),
+			newApp: newApp(
+				warg.NewSubCmd(
+					"run",
+					"Run",
+					warg.Unimplemented(),
+					warg.NewCmdFlag("--mode", "Mode", scalar.String(scalar.Choices("a", "b", "d"))),
+					warg.NewCmdFlag("--count", "Count", scalar.Int()),
+					warg.NewCmdFlag("--name", "Name", scalar.String(), warg.Required(), warg.EnvVars("APP_NAME")),
+				),
+			),
+			expected: []compat.Change{
+				{Kind: compat.ChangeBreaking, Path: "myapp run --count", Message: "type changed from scalar string to scalar int"},
+				{Kind: compat.ChangeBreaking, Path: "myapp run --mode", Message: "choice c removed"},
+				{Kind: compat.ChangeBreaking, Path: "myapp run --name", Message: "flag is now required"},
+				{Kind: compat.ChangeBreaking, Path: "myapp run --name", Message: "env var NAME removed"},
+				{Kind: compat.ChangeAdditive, Path: "myapp run --count", Message: "flag is no longer required"},
+				{Kind: compat.ChangeAdditive, Path: "myapp run --mode", Message: "choice d added"},
+				{Kind: compat.ChangeAdditive, Path: "myapp run --name", Message: "env var APP_NAME added"},
+			},
+		},
+		{
+			name: "requiredFlagAdded",
+			oldApp: newApp(
+				warg.NewSubCmd("run", "Run", warg.Unimplemented()),
+			),
+			newApp: newApp(
+				warg.NewSubCmd(
+					"run",
+					"Run",
+					warg.Unimplemented(),
+					warg.NewCmdFlag("--a", "A", scalar.String(), warg.Required()),
+					warg.NewCmdFlag("--b", "B", scalar.String()),
+				),
+			),
+			expected: []compat.Change{
+				{Kind: compat.ChangeBreaking, Path: "myapp run --a", Message: "required flag added"},
+				{Kind: compat.ChangeAdditive, Path: "myapp run --b", Message: "flag added"},
+			},
+		},
+		{
+			name: "switchChanged",
+			oldApp: newApp(
+				warg.NewSubCmd(
+					"run",
+					"Run",
+					warg.Unimplemented(),
+					warg.NewCmdFlag("--dry-run", "Dry run", scalar.Bool()),
+					warg.NewCmdFlag("--verbose", "Verbose", scalar.Bool(), warg.Switch()),
+				),
+			),
+			newApp: newApp(
+				warg.NewSubCmd(
+					"run",
+					"Run",
+					warg.Unimplemented(),
+					warg.NewCmdFlag("--dry-run", "Dry run", scalar.Bool(), warg.Switch()),
+					warg.NewCmdFlag("--verbose", "Verbose", scalar.Bool()),
+				),
+			),
+			expected: []compat.Change{
+				{Kind: compat.ChangeBreaking, Path: "myapp run --dry-run", Message: "flag is now a switch"},
+				{Kind: compat.ChangeBreaking, Path: "myapp run --verbose", Message: "flag is no longer a switch"},
+			},
+		},
+		{
+			name: "flagMovedToSection",
+			oldApp: newApp(
+				warg.NewSubSection(
+					"k8s",
+					"Kubernetes",
+					warg.NewSubCmd("get", "Get", warg.Unimplemented(), warg.NewCmdFlag("--cluster", "Cluster", scalar.String())),
+				),
+			),
+			newApp: newApp(
+				warg.NewSubSection(
+					"k8s",
+					"Kubernetes",
+					warg.NewSectionFlag("--cluster", "Cluster", scalar.String()),
+					warg.NewSubCmd("get", "Get", warg.Unimplemented()),
+				),
+			),
+			expected: nil,
+		},
+		{
+			name: "sectionFlagRemovedReportedOnce",
+			oldApp: newApp(
+				warg.NewSubSection(
+					"k8s",
+					"Kubernetes",
+					warg.NewSectionFlag("--cluster", "Cluster", scalar.String()),
+					warg.NewSubCmd("get", "Get", warg.Unimplemented()),
+					warg.NewSubCmd("list", "List", warg.Unimplemented()),
+				),
+			),
+			newApp: newApp(
+				warg.NewSubSection(
+					"k8s",
+					"Kubernetes",
+					warg.NewSubCmd("get", "Get", warg.Unimplemented()),
+					warg.NewSubCmd("list", "List", warg.Unimplemented()),
+				),
+			),
+			expected: []compat.Change{
+				{Kind: compat.ChangeBreaking, Path: "myapp k8s --cluster", Message: "flag removed"},
+			},
+		},
+		{
+			name: "cmdsAndSections",
+			oldApp: newApp(
+				warg.NewSubCmd("rm", "Remove", warg.Unimplemented()),
+				warg.NewSubCmd("ls", "List", warg.Unimplemented(), warg.CmdAliases("list")),
+				warg.NewSubSection("db", "Database", warg.NewSubCmd("migrate", "Migrate", warg.Unimplemented())),
+			),
+			newApp: newApp(
+				warg.NewSubCmd("remove", "Remove", warg.Unimplemented(), warg.CmdAliases("rm")),
+				warg.NewSubCmd("ls", "List", warg.Unimplemented()),
+				warg.NewSubSection("k8s", "Kubernetes", warg.NewSubCmd("get", "Get", warg.Unimplemented())),
+			),
+			expected: []compat.Change{
+				{Kind: compat.ChangeBreaking, Path: "myapp ls", Message: "command alias list removed"},
+				{Kind: compat.ChangeBreaking, Path: "myapp db", Message: "section removed"},
+				{Kind: compat.ChangeAdditive, Path: "myapp remove", Message: "command added"},
+				{Kind: compat.ChangeAdditive, Path: "myapp k8s", Message: "section added"},
+			},
+		},
+		{
+			name: "cmdArgs",
+			oldApp: newApp(
+				warg.NewSubCmd(
+					"cp",
+					"Copy",
+					warg.Unimplemented(),
+					warg.CmdPositional("SRC", "Source", scalar.String()),
+					warg.NewCmdFlag("--a", "A", scalar.Bool()),
+					warg.NewCmdFlag("--b", "B", scalar.Bool()),
+					warg.AllowForwardedArgs(),
+				),
+			),
+			newApp: newApp(
+				warg.NewSubCmd(
+					"cp",
+					"Copy",
+					warg.Unimplemented(),
+					warg.CmdPositional("SRC", "Source", scalar.String()),
+					warg.CmdPositional("DST", "Destination", scalar.String(), warg.PositionalRequired()),
+					warg.NewCmdFlag("--a", "A", scalar.Bool()),
+					warg.NewCmdFlag("--b", "B", scalar.Bool()),
+					warg.MutuallyExclusive("--a", "--b"),
+				),
+			),
+			expected: []compat.Change{
+				{Kind: compat.ChangeBreaking, Path: "myapp cp DST", Message: "required positional added"},
+				{Kind: compat.ChangeBreaking, Path: "myapp cp", Message: "forwarded args are no longer allowed"},
+				{Kind: compat.ChangeBreaking, Path: "myapp cp", Message: "constraint mutually exclusive: --a, --b added"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := compat.Compare(tt.oldApp.Describe(), tt.newApp.Describe())
+			require.Nil(t, err)
+			require.Equal(t, tt.expected, report.Changes)
+		})
+	}
+}
+
+func TestCompareJSON(t *testing.T) {
+	oldApp := newApp(warg.NewSubCmd("run", "Run", warg.Unimplemented()))
+	oldJSON, err := json.Marshal(oldApp.Describe())
+	require.Nil(t, err)
+
+	newDesc := oldApp.Describe()
+	newDesc.SchemaVersion = warg.DescriptionSchemaVersion + 1
+	newJSON, err := json.Marshal(newDesc)
+	require.Nil(t, err)
+
+	report, err := compat.CompareJSON(oldJSON, oldJSON)
+	require.Nil(t, err)
+	require.Empty(t, report.Changes)
+
+	_, err = compat.CompareJSON(oldJSON, newJSON)
+	require.Error(t, err)
+}
+
+func TestRequireCompatible(t *testing.T) {
+	app := newApp(
+		warg.NewSubCmd(
+			"run",
+			"Run",
+			warg.Unimplemented(),
+			warg.NewCmdFlag("--mode", "Mode", scalar.String(scalar.Choices("fast", "safe"))),
+		),
+	)
+	compat.RequireCompatible(t, compat.RequireCompatibleArgs{
+		App:               &app,
+		UpdateDescription: os.Getenv("WARG_TEST_UPDATE_GOLDEN") != "",
+	})
+}
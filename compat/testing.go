@@ -0,0 +1,76 @@
+package compat
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.bbkane.com/warg"
+)
+
+// RequireCompatibleArgs holds configuration for [RequireCompatible].
+type RequireCompatibleArgs struct {
+	App *warg.App
+
+	// UpdateDescription overwrites the saved description with the app's current description when true.
+	// Use it to accept breaking changes (for example, for a new major version) or to record additive ones.
+	UpdateDescription bool
+}
+
+// RequireCompatible compares the app's current description (see [warg.App.Describe]) against the one
+// saved in testdata/<TestName>/description.json, and fails the test if there are breaking changes.
+// Additive changes are logged. Set RequireCompatibleArgs.UpdateDescription to save the current description.
+//
+// Example usage:
+//
+//	func TestCompatible(t *testing.T) {
+//		app := buildApp()
+//		compat.RequireCompatible(t, compat.RequireCompatibleArgs{
+//			App:               &app,
+//			UpdateDescription: os.Getenv("WARG_TEST_UPDATE_GOLDEN") != "",
+//		})
+//	}
+func RequireCompatible(t *testing.T, args RequireCompatibleArgs) {
+	descPath := filepath.Join("testdata", t.Name(), "description.json")
+	descPath, err := filepath.Abs(descPath)
+	require.Nil(t, err)
+
+	actual, err := json.MarshalIndent(args.App.Describe(), "", "  ")
+	require.Nil(t, err)
+	actual = append(actual, '\n')
+
+	if args.UpdateDescription {
+		mkdirErr := os.MkdirAll(filepath.Dir(descPath), 0700)
+		require.Nil(t, mkdirErr)
+
+		writeErr := os.WriteFile(descPath, actual, 0600)
+		require.Nil(t, writeErr)
+	}
+
+	expected, err := os.ReadFile(descPath)
+	require.Nil(t, err, "Could not read saved description. Set RequireCompatibleArgs.UpdateDescription to create it")
+
+	report, err := CompareJSON(expected, actual)
+	require.Nil(t, err)
+
+	for _, change := range report.Additive() {
+		t.Log(change.String())
+	}
+
+	breaking := report.Breaking()
+	if len(breaking) > 0 {
+		lines := make([]string, 0, len(breaking))
+		for _, change := range breaking {
+			lines = append(lines, "  "+change.String())
+		}
+		t.Fatalf(
+			"Breaking changes compared to %s:\n%s\nSet RequireCompatibleArgs.UpdateDescription to accept them",
+			descPath,
+			strings.Join(lines, "\n"),
+		)
+	}
+}
@@ -1,12 +1,15 @@
 package completion_test
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	"go.bbkane.com/warg"
 	"go.bbkane.com/warg/completion"
 	"go.bbkane.com/warg/completion/internal/testapp"
+	"go.bbkane.com/warg/value/scalar"
 )
 
 func TestApp_Completions(t *testing.T) {
@@ -80,6 +83,10 @@ func TestApp_Completions(t *testing.T) {
 						Name:        "command3",
 						Description: "command with AllowForwardedArgs enabled",
 					},
+					{
+						Name:        "command4",
+						Description: "command with positional args",
+					},
 					{
 						Name:        "--help",
 						Description: "Print help",
@@ -100,6 +107,44 @@ func TestApp_Completions(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:        "cmdPositional",
+			args:        []string{"section1", "command4"},
+			expectedErr: false,
+			expectedCandidates: &completion.Candidates{
+				Type: completion.Type_Values,
+				Values: []completion.Candidate{
+					{Name: "fast", Description: ""},
+					{Name: "slow", Description: ""},
+				},
+			},
+		},
+		{
+			name:        "cmdPositionalFilled",
+			args:        []string{"section1", "command4", "fast"},
+			expectedErr: false,
+			expectedCandidates: &completion.Candidates{
+				Type: completion.Type_ValuesDescriptions,
+				Values: []completion.Candidate{
+					{Name: "--color", Description: "color help"},
+					{Name: "--no-color", Description: "Set --color to false"},
+					globalFlagcompletion,
+					helpCompletion,
+				},
+			},
+		},
+		{
+			name:        "cmdSwitchPassed",
+			args:        []string{"section1", "command4", "--color"},
+			expectedErr: false,
+			expectedCandidates: &completion.Candidates{
+				Type: completion.Type_Values,
+				Values: []completion.Candidate{
+					{Name: "fast", Description: ""},
+					{Name: "slow", Description: ""},
+				},
+			},
+		},
 		{
 			name:        "cmdFlagName",
 			args:        []string{"command1"},
@@ -218,6 +263,14 @@ func TestApp_Completions(t *testing.T) {
 						Name:        "detailed",
 						Description: "",
 					},
+					{
+						Name:        "explain",
+						Description: "",
+					},
+					{
+						Name:        "json",
+						Description: "",
+					},
 					{
 						Name:        "outline",
 						Description: "",
@@ -256,3 +309,174 @@ func TestApp_Completions(t *testing.T) {
 	}
 
 }
+
+func TestApp_Completions_flagEqualsValue(t *testing.T) {
+	app := testapp.BuildApp()
+
+	actualCandidates, actualErr := app.Complete(
+		[]string{"command1"},
+		"--flag1=al",
+		warg.ParseWithLookupEnv(warg.LookupMap(nil)),
+	)
+	require.NoError(t, actualErr)
+	require.Equal(
+		t,
+		&completion.Candidates{
+			Type: completion.Type_Values,
+			Values: []completion.Candidate{
+				{Name: "--flag1=alpha", Description: ""},
+				{Name: "--flag1=beta", Description: ""},
+				{Name: "--flag1=gamma", Description: ""},
+			},
+		},
+		actualCandidates,
+	)
+}
+
+func TestApp_Completions_flagEqualsPath(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "notes"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), nil, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other.txt"), nil, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".notes"), nil, 0644))
+	sep := string(filepath.Separator)
+
+	app := warg.New(
+		"newAppName",
+		"v1.0.0",
+		warg.NewSection(
+			"help for test",
+			warg.NewSubCmd(
+				"cmd",
+				"cmd help",
+				warg.Unimplemented(),
+				warg.NewCmdFlag("--path", "path help", scalar.Path()),
+				warg.NewCmdFlag("--dir", "dir help", scalar.Path(), warg.FlagCompletions(warg.CompletionsDirectories())),
+			),
+		),
+		warg.SkipAll(),
+	)
+
+	tests := []struct {
+		name               string
+		partiallyTypedArg  string
+		expectedCandidates *completion.Candidates
+	}{
+		{
+			name:              "filesAndDirectories",
+			partiallyTypedArg: "--path=" + dir + sep + "no",
+			expectedCandidates: &completion.Candidates{
+				Type: completion.Type_Values,
+				Values: []completion.Candidate{
+					{Name: "--path=" + dir + sep + "notes" + sep, Description: ""},
+					{Name: "--path=" + dir + sep + "notes.txt", Description: ""},
+				},
+			},
+		},
+		{
+			name:              "directoriesOnly",
+			partiallyTypedArg: "--dir=" + dir + sep,
+			expectedCandidates: &completion.Candidates{
+				Type: completion.Type_Values,
+				Values: []completion.Candidate{
+					{Name: "--dir=" + dir + sep + "notes" + sep, Description: ""},
+				},
+			},
+		},
+		{
+			name:              "hiddenOnceDotTyped",
+			partiallyTypedArg: "--path=" + dir + sep + ".",
+			expectedCandidates: &completion.Candidates{
+				Type: completion.Type_Values,
+				Values: []completion.Candidate{
+					{Name: "--path=" + dir + sep + ".notes", Description: ""},
+				},
+			},
+		},
+		{
+			name:              "missingDirectory",
+			partiallyTypedArg: "--path=" + filepath.Join(dir, "missing") + sep,
+			expectedCandidates: &completion.Candidates{
+				Type:   completion.Type_None,
+				Values: nil,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actualCandidates, actualErr := app.Complete(
+				[]string{"cmd"},
+				tt.partiallyTypedArg,
+				warg.ParseWithLookupEnv(warg.LookupMap(nil)),
+			)
+			require.NoError(t, actualErr)
+			require.Equal(t, tt.expectedCandidates, actualCandidates)
+		})
+	}
+}
+
+func TestApp_Completions_hidden(t *testing.T) {
+	app := warg.New(
+		"newAppName",
+		"v1.0.0",
+		warg.NewSection(
+			"root help",
+			warg.NewSubCmd(
+				"visible",
+				"visible help",
+				warg.Unimplemented(),
+				warg.NewCmdFlag("--shown", "shown help", scalar.String()),
+				warg.NewCmdFlag("--secret", "secret help", scalar.String(), warg.FlagHidden()),
+			),
+			warg.NewSubCmd("hidden", "hidden help", warg.Unimplemented(), warg.CmdHidden()),
+			warg.NewSubSection(
+				"internal",
+				"internal help",
+				warg.NewSubCmd("debug", "debug help", warg.Unimplemented()),
+				warg.SectionHidden(),
+			),
+		),
+		warg.SkipAll(),
+	)
+
+	tests := []struct {
+		name               string
+		args               []string
+		expectedCandidates *completion.Candidates
+	}{
+		{
+			name: "sectionsAndCmds",
+			args: []string{},
+			expectedCandidates: &completion.Candidates{
+				Type: completion.Type_ValuesDescriptions,
+				Values: []completion.Candidate{
+					{Name: "visible", Description: "visible help"},
+					{Name: "--help", Description: "Print help"},
+				},
+			},
+		},
+		{
+			name: "flags",
+			args: []string{"visible"},
+			expectedCandidates: &completion.Candidates{
+				Type: completion.Type_ValuesDescriptions,
+				Values: []completion.Candidate{
+					{Name: "--shown", Description: "shown help"},
+					{Name: "--help", Description: "Print help"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actualCandidates, actualErr := app.Complete(
+				tt.args,
+				"",
+				warg.ParseWithLookupEnv(warg.LookupMap(nil)),
+			)
+			require.NoError(t, actualErr)
+			require.Equal(t, tt.expectedCandidates, actualCandidates)
+		})
+	}
+}
@@ -113,6 +113,25 @@ func BuildApp() *warg.App {
 					warg.Unimplemented(),
 					warg.AllowForwardedArgs(),
 				),
+				warg.NewSubCmd(
+					"command4",
+					"command with positional args",
+					warg.Unimplemented(),
+					warg.CmdPositional(
+						"SPEED",
+						"speed help",
+						scalar.String(
+							scalar.Choices("fast", "slow"),
+						),
+						warg.PositionalRequired(),
+					),
+					warg.NewCmdFlag(
+						"--color",
+						"color help",
+						scalar.Bool(),
+						warg.Negatable(),
+					),
+				),
 			),
 		),
 		warg.NewGlobalFlag(
@@ -1,10 +1,14 @@
 // Package config defines the interface for reading flag values from configuration files.
 package config
 
+import "sort"
+
 // SearchResult holds a value found at a config path.
 type SearchResult struct {
 	// IFace holds the decoded value (type depends on the config format).
 	IFace interface{}
+	// FilePath is the config file the value was found in.
+	FilePath string
 }
 
 // Reader searches a parsed config file for values at a given dot-separated path.
@@ -17,3 +21,20 @@ type Reader interface {
 // cannot be read or parsed. A non-existent file is not an error (returns a Reader
 // that finds nothing).
 type NewReader func(filePath string) (Reader, error)
+
+// RootKeysLister is optionally implemented by a [Reader] to list the top-level keys in its config file.
+// Tools like the `config validate` command use it to find keys that don't match any flag's ConfigPath.
+type RootKeysLister interface {
+	RootKeys() []string
+}
+
+// SortedKeys returns the sorted keys of a decoded config file. Readers that decode into a map can
+// implement [RootKeysLister] with it.
+func SortedKeys(data map[string]interface{}) []string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
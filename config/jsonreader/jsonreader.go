@@ -15,7 +15,8 @@ import (
 type configMap = map[string]interface{}
 
 type jsonConfigReader struct {
-	data configMap
+	data     configMap
+	filePath string
 }
 
 // New creates a [config.Reader] that reads flag values from a JSON file at filePath.
@@ -23,7 +24,8 @@ type jsonConfigReader struct {
 // Uses json.Number for numeric precision.
 func New(filePath string) (config.Reader, error) {
 	cr := &jsonConfigReader{
-		data: nil,
+		data:     nil,
+		filePath: filePath,
 	}
 
 	content, err := os.ReadFile(filePath)
@@ -44,6 +46,11 @@ func New(filePath string) (config.Reader, error) {
 	return cr, nil
 }
 
+// RootKeys returns the sorted top-level keys in the config file. It implements [config.RootKeysLister].
+func (cr *jsonConfigReader) RootKeys() []string {
+	return config.SortedKeys(cr.data)
+}
+
 func (cr *jsonConfigReader) Search(path string) (*config.SearchResult, error) {
 	data := cr.data
 	tokens, err := tokenize.Tokenize(path)
@@ -89,5 +96,5 @@ func (cr *jsonConfigReader) Search(path string) (*config.SearchResult, error) {
 			return nil, nil
 		}
 	}
-	return &config.SearchResult{IFace: current}, nil
+	return &config.SearchResult{IFace: current, FilePath: cr.filePath}, nil
 }
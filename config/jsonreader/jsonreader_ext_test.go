@@ -25,7 +25,8 @@ func TestSearch(t *testing.T) {
 			searchPath:          "key",
 			expectedCreationErr: false,
 			expectedSearchResult: &config.SearchResult{
-				IFace: "value",
+				IFace:    "value",
+				FilePath: "testdata/TestSearch.json",
 			},
 			expectedSearchErr: false,
 		},
@@ -43,7 +44,8 @@ func TestSearch(t *testing.T) {
 			searchPath:          "key1.key2",
 			expectedCreationErr: false,
 			expectedSearchResult: &config.SearchResult{
-				IFace: json.Number("1"),
+				IFace:    json.Number("1"),
+				FilePath: "testdata/TestSearch.json",
 			},
 			expectedSearchErr: false,
 		},
@@ -53,7 +55,8 @@ func TestSearch(t *testing.T) {
 			searchPath:          "map_val",
 			expectedCreationErr: false,
 			expectedSearchResult: &config.SearchResult{
-				IFace: map[string]interface{}{"a": json.Number("1")},
+				IFace:    map[string]interface{}{"a": json.Number("1")},
+				FilePath: "testdata/TestSearch.json",
 			},
 			expectedSearchErr: false,
 		},
@@ -0,0 +1,30 @@
+package config
+
+type layeredReader struct {
+	readers []Reader
+}
+
+// NewLayeredReader creates a [Reader] that searches multiple readers, such as a system config,
+// a user config, and a project-local config. Later readers take precedence: Search returns
+// the result from the last reader that finds the path. Nil readers are skipped.
+func NewLayeredReader(readers ...Reader) Reader {
+	return &layeredReader{
+		readers: readers,
+	}
+}
+
+func (lr *layeredReader) Search(path string) (*SearchResult, error) {
+	for i := len(lr.readers) - 1; i >= 0; i-- {
+		if lr.readers[i] == nil {
+			continue
+		}
+		res, err := lr.readers[i].Search(path)
+		if err != nil {
+			return nil, err
+		}
+		if res != nil {
+			return res, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,143 @@
+package tomlreader
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/BurntSushi/toml"
+	"go.bbkane.com/warg/colerr"
+	"go.bbkane.com/warg/config"
+	"go.bbkane.com/warg/config/internal/tokenize"
+)
+
+// BurntSushi/toml decodes tables into map[string]interface{}
+type configMap = map[string]interface{}
+
+type tomlConfigReader struct {
+	data     configMap
+	filePath string
+}
+
+// New creates a [config.Reader] that reads flag values from a TOML file at filePath.
+// If the file does not exist, the returned reader finds no values (not an error).
+// Arrays of tables are normalized to []interface{} so slice values can read them.
+// Integers decode as int64 and datetimes as time.Time.
+func New(filePath string) (config.Reader, error) {
+	cr := &tomlConfigReader{
+		data:     nil,
+		filePath: filePath,
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		// the file not existing is ok
+		return cr, nil
+	}
+
+	_, err = toml.Decode(string(content), &cr.data)
+	if err != nil {
+		return nil, err
+	}
+	cr.data = normalize(cr.data).(configMap)
+	return cr, nil
+}
+
+// normalize converts the types BurntSushi/toml produces into the ones the rest of warg expects.
+// In particular, arrays of tables decode as []map[string]interface{}, but slice values
+// and the key[] search syntax expect []interface{}.
+func normalize(iFace interface{}) interface{} {
+	switch under := iFace.(type) {
+	case configMap:
+		for k, v := range under {
+			under[k] = normalize(v)
+		}
+		return under
+	case []configMap:
+		ret := make([]interface{}, 0, len(under))
+		for _, e := range under {
+			ret = append(ret, normalize(e))
+		}
+		return ret
+	case []interface{}:
+		for i, e := range under {
+			under[i] = normalize(e)
+		}
+		return under
+	default:
+		return iFace
+	}
+}
+
+// RootKeys returns the sorted top-level keys in the config file. It implements [config.RootKeysLister].
+func (cr *tomlConfigReader) RootKeys() []string {
+	return config.SortedKeys(cr.data)
+}
+
+func (cr *tomlConfigReader) Search(path string) (*config.SearchResult, error) {
+	data := cr.data
+	tokens, err := tokenize.Tokenize(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var current interface{} = data
+	for i, token := range tokens {
+
+		// special case: tokenize guarantees [] is followed only by the last key,
+		// so collect that key from each element of the array of tables and return
+		if token.Type == tokenize.TokenTypeSlice {
+			return cr.searchSlice(current, tokens[i+1], path)
+		}
+
+		currentMap, ok := current.(configMap)
+		if !ok {
+			return nil, colerr.NewWrappedf(
+				nil,
+				"expecting map[string]interface{}: \n  actual type %s\n  actual value: %s\n  path: %s\n  token: %s",
+				fmt.Sprintf("%T", current), fmt.Sprintf("%#v", current), fmt.Sprintf("%v", path), fmt.Sprintf("%v", token),
+			)
+		}
+
+		next, exists := currentMap[token.Text]
+		current = next
+		if !exists {
+			return nil, nil
+		}
+	}
+
+	return &config.SearchResult{IFace: current, FilePath: cr.filePath}, nil
+}
+
+// searchSlice returns lastToken's value from each table in current (which must be an array of tables).
+func (cr *tomlConfigReader) searchSlice(current interface{}, lastToken tokenize.Token, path string) (*config.SearchResult, error) {
+	currentSlice, ok := current.([]interface{})
+	if !ok {
+		return nil, colerr.NewWrappedf(
+			nil,
+			"expecting []interface{}: \n  actual type %s\n  actual value: %s\n  path: %s",
+			fmt.Sprintf("%T", current), fmt.Sprintf("%#v", current), fmt.Sprintf("%v", path),
+		)
+	}
+
+	ret := make([]interface{}, 0, len(currentSlice))
+	for i, e := range currentSlice {
+		elementMap, ok := e.(configMap)
+		if !ok {
+			return nil, colerr.NewWrappedf(
+				nil,
+				"expecting map[string]interface{} at index %s: \n  actual type %s\n  path: %s",
+				fmt.Sprintf("%d", i), fmt.Sprintf("%T", e), fmt.Sprintf("%v", path),
+			)
+		}
+		val, exists := elementMap[lastToken.Text]
+		if !exists {
+			return nil, colerr.NewWrappedf(
+				nil,
+				"missing key at index %s: path: %s: key: %s",
+				fmt.Sprintf("%d", i), fmt.Sprintf("%v", path), lastToken.Text,
+			)
+		}
+		ret = append(ret, val)
+	}
+	return &config.SearchResult{IFace: ret, FilePath: cr.filePath}, nil
+}
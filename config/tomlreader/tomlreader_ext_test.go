@@ -0,0 +1,167 @@
+package tomlreader_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.bbkane.com/warg/config"
+	"go.bbkane.com/warg/config/tomlreader"
+	"go.bbkane.com/warg/value/contained"
+)
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		name                 string
+		filePath             string
+		searchPath           string
+		expectedCreationErr  bool
+		expectedSearchResult *config.SearchResult
+		expectedSearchErr    bool
+	}{
+		{
+			name:                "one_key",
+			filePath:            "testdata/TestSearch.toml",
+			searchPath:          "key",
+			expectedCreationErr: false,
+			expectedSearchResult: &config.SearchResult{
+				IFace:    "value",
+				FilePath: "testdata/TestSearch.toml",
+			},
+			expectedSearchErr: false,
+		},
+		{
+			name:                "int64_key",
+			filePath:            "testdata/TestSearch.toml",
+			searchPath:          "int64_key",
+			expectedCreationErr: false,
+			expectedSearchResult: &config.SearchResult{
+				IFace:    int64(42),
+				FilePath: "testdata/TestSearch.toml",
+			},
+			expectedSearchErr: false,
+		},
+		{
+			name:                "int64_key_negative",
+			filePath:            "testdata/TestSearch.toml",
+			searchPath:          "int64_key_negative",
+			expectedCreationErr: false,
+			expectedSearchResult: &config.SearchResult{
+				IFace:    int64(-42),
+				FilePath: "testdata/TestSearch.toml",
+			},
+			expectedSearchErr: false,
+		},
+		{
+			name:                "datetime_key",
+			filePath:            "testdata/TestSearch.toml",
+			searchPath:          "datetime_key",
+			expectedCreationErr: false,
+			expectedSearchResult: &config.SearchResult{
+				IFace:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+				FilePath: "testdata/TestSearch.toml",
+			},
+			expectedSearchErr: false,
+		},
+		{
+			name:                 "nil_map",
+			filePath:             "non-existant",
+			searchPath:           "non-existant",
+			expectedCreationErr:  false, // It's ok to not have a config file
+			expectedSearchResult: nil,
+			expectedSearchErr:    false,
+		},
+		{
+			name:                "two_keys",
+			filePath:            "testdata/TestSearch.toml",
+			searchPath:          "key1.key2",
+			expectedCreationErr: false,
+			expectedSearchResult: &config.SearchResult{
+				IFace:    int64(1),
+				FilePath: "testdata/TestSearch.toml",
+			},
+			expectedSearchErr: false,
+		},
+		{
+			name:                "map_val",
+			filePath:            "testdata/TestSearch.toml",
+			searchPath:          "map_val",
+			expectedCreationErr: false,
+			expectedSearchResult: &config.SearchResult{
+				IFace:    map[string]interface{}{"a": int64(1)},
+				FilePath: "testdata/TestSearch.toml",
+			},
+			expectedSearchErr: false,
+		},
+		{
+			name:                "int_slice",
+			filePath:            "testdata/TestSearch.toml",
+			searchPath:          "int_slice",
+			expectedCreationErr: false,
+			expectedSearchResult: &config.SearchResult{
+				IFace:    []interface{}{int64(1), int64(2), int64(3)},
+				FilePath: "testdata/TestSearch.toml",
+			},
+			expectedSearchErr: false,
+		},
+		{
+			name:                "slice_key",
+			filePath:            "testdata/TestSearch.toml",
+			searchPath:          "subreddits[].name",
+			expectedCreationErr: false,
+			expectedSearchResult: &config.SearchResult{
+				IFace:    []interface{}{"earthporn", "wallpapers"},
+				FilePath: "testdata/TestSearch.toml",
+			},
+			expectedSearchErr: false,
+		},
+		{
+			name:                 "slice_key_not_array",
+			filePath:             "testdata/TestSearch.toml",
+			searchPath:           "map_val[].a",
+			expectedCreationErr:  false,
+			expectedSearchResult: nil,
+			expectedSearchErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr, err := tomlreader.New(tt.filePath)
+
+			if tt.expectedCreationErr {
+				require.NotNil(t, err)
+			} else {
+				require.Nil(t, err)
+			}
+
+			res, err := cr.Search(tt.searchPath)
+
+			if tt.expectedSearchErr {
+				require.NotNil(t, err)
+			} else {
+				require.Nil(t, err)
+			}
+
+			require.Equal(t, tt.expectedSearchResult, res)
+		})
+	}
+}
+
+func TestFromIFace(t *testing.T) {
+	cr, err := tomlreader.New("testdata/TestSearch.toml")
+	require.Nil(t, err)
+
+	res, err := cr.Search("int64_key")
+	require.Nil(t, err)
+	i, err := contained.Int().FromIFace(res.IFace)
+	require.Nil(t, err)
+	require.Equal(t, 42, i)
+
+	res, err = cr.Search("datetime_key")
+	require.Nil(t, err)
+	dt, err := contained.DateTimeRFC3339().FromIFace(res.IFace)
+	require.Nil(t, err)
+	require.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), dt)
+}
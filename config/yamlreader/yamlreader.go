@@ -14,7 +14,8 @@ import (
 type configMap = map[string]interface{}
 
 type yamlConfigReader struct {
-	data configMap
+	data     configMap
+	filePath string
 }
 
 // New creates a [config.Reader] that reads flag values from a YAML file at filePath.
@@ -22,7 +23,8 @@ type yamlConfigReader struct {
 // Uses strict YAML parsing.
 func New(filePath string) (config.Reader, error) {
 	cr := &yamlConfigReader{
-		data: nil,
+		data:     nil,
+		filePath: filePath,
 	}
 
 	content, err := os.ReadFile(filePath)
@@ -42,6 +44,11 @@ func New(filePath string) (config.Reader, error) {
 
 }
 
+// RootKeys returns the sorted top-level keys in the config file. It implements [config.RootKeysLister].
+func (cr *yamlConfigReader) RootKeys() []string {
+	return config.SortedKeys(cr.data)
+}
+
 func (cr *yamlConfigReader) Search(path string) (*config.SearchResult, error) {
 	data := cr.data
 	tokens, err := tokenize.Tokenize(path)
@@ -84,5 +91,5 @@ func (cr *yamlConfigReader) Search(path string) (*config.SearchResult, error) {
 	if currentConfigMap, ok := current.(configMap); ok {
 		current = currentConfigMap
 	}
-	return &config.SearchResult{IFace: current}, nil
+	return &config.SearchResult{IFace: current, FilePath: cr.filePath}, nil
 }
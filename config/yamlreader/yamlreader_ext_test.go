@@ -24,7 +24,8 @@ func TestSearch(t *testing.T) {
 			searchPath:          "key",
 			expectedCreationErr: false,
 			expectedSearchResult: &config.SearchResult{
-				IFace: "value",
+				IFace:    "value",
+				FilePath: "testdata/TestSearch.yaml",
 			},
 			expectedSearchErr: false,
 		},
@@ -34,7 +35,8 @@ func TestSearch(t *testing.T) {
 			searchPath:          "uint64_key",
 			expectedCreationErr: false,
 			expectedSearchResult: &config.SearchResult{
-				IFace: uint64(42),
+				IFace:    uint64(42),
+				FilePath: "testdata/TestSearch.yaml",
 			},
 			expectedSearchErr: false,
 		},
@@ -44,7 +46,8 @@ func TestSearch(t *testing.T) {
 			searchPath:          "int64_key_negative",
 			expectedCreationErr: false,
 			expectedSearchResult: &config.SearchResult{
-				IFace: int64(-42),
+				IFace:    int64(-42),
+				FilePath: "testdata/TestSearch.yaml",
 			},
 			expectedSearchErr: false,
 		},
@@ -62,7 +65,8 @@ func TestSearch(t *testing.T) {
 			searchPath:          "key1.key2",
 			expectedCreationErr: false,
 			expectedSearchResult: &config.SearchResult{
-				IFace: uint64(1),
+				IFace:    uint64(1),
+				FilePath: "testdata/TestSearch.yaml",
 			},
 			expectedSearchErr: false,
 		},
@@ -72,7 +76,8 @@ func TestSearch(t *testing.T) {
 			searchPath:          "map_val",
 			expectedCreationErr: false,
 			expectedSearchResult: &config.SearchResult{
-				IFace: map[string]interface{}{"a": uint64(1)},
+				IFace:    map[string]interface{}{"a": uint64(1)},
+				FilePath: "testdata/TestSearch.yaml",
 			},
 			expectedSearchErr: false,
 		},
@@ -0,0 +1,541 @@
+package warg
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+
+	"go.bbkane.com/warg/colerr"
+	"go.bbkane.com/warg/config"
+	"go.bbkane.com/warg/path"
+	"go.bbkane.com/warg/value"
+	"go.bbkane.com/warg/value/scalar"
+)
+
+// configCmdsSection returns the "config" section added by [ConfigCmds].
+func configCmdsSection() SectionOpt {
+	return NewSubSection(
+		"config",
+		"Work with config files",
+		NewSubCmd(
+			"init",
+			"Print a config file skeleton with defaults filled in",
+			configInitCmdAction,
+			CmdSkipInheritedMiddlewares(),
+			CmdHelpLong("Print a config file skeleton generated from the flags that declare a config path. Flags with defaults are filled in. In YAML, flags without defaults are commented out; JSON has no comments, so they are omitted."),
+			NewCmdFlag(
+				"--format",
+				"Config file format",
+				scalar.String(
+					scalar.Choices("yaml", "json"),
+					scalar.Default("yaml"),
+				),
+			),
+		),
+		NewSubCmd(
+			"validate",
+			"Check config files for unknown keys and invalid values",
+			configValidateCmdAction,
+			CmdSkipInheritedMiddlewares(),
+			CmdHelpLong("Check every config file the app reads (files added with ConfigFile and the file passed to the config flag) for keys that don't match any flag's config path, and for values that can't be converted to their flag's type. A config file passed explicitly (not from the config flag's default) must exist."),
+			CmdSkipConfig(),
+		),
+		NewSubCmd(
+			"schema",
+			"Print a JSON Schema for the config file",
+			configSchemaCmdAction,
+			CmdSkipInheritedMiddlewares(),
+			CmdHelpLong("Print a JSON Schema generated from the flags that declare a config path, so editors can validate and complete config files. For YAML files, save it and add a '# yaml-language-server: $schema=<path>' comment to the top of the config file."),
+		),
+	)
+}
+
+// configInitLeaf holds what config init needs to know about a flag with a ConfigPath.
+type configInitLeaf struct {
+	flagName  string
+	helpShort string
+	typeDesc  string
+	// defaultVal is the default formatted as a YAML/JSON value, or "" if there is no default
+	defaultVal string
+	// defaultElems are the formatted default elements of slice values. Used for key[] paths,
+	// where each element of the slice goes in a different table.
+	defaultElems []string
+}
+
+// configInitNode is one element of a config path, such as "key" or "key[]".
+type configInitNode struct {
+	// tableSlice means this node was written "key[]" and holds a list of tables
+	tableSlice bool
+	children   map[string]*configInitNode
+	leaf       *configInitLeaf
+}
+
+func newConfigInitNode() *configInitNode {
+	return &configInitNode{
+		tableSlice: false,
+		children:   make(map[string]*configInitNode),
+		leaf:       nil,
+	}
+}
+
+// numElems returns the number of tables a key[] node holds: the length of its longest default.
+func (n *configInitNode) numElems() int {
+	num := 0
+	for _, child := range n.children {
+		if child.leaf != nil {
+			num = max(num, len(child.leaf.defaultElems))
+		}
+	}
+	return num
+}
+
+// hasDefault reports whether this node or any of its descendants has a default value.
+// Nodes without defaults are commented out (YAML) or omitted (JSON).
+func (n *configInitNode) hasDefault() bool {
+	if n.leaf != nil {
+		return n.leaf.defaultVal != ""
+	}
+	if n.tableSlice {
+		return n.numElems() > 0
+	}
+	for _, child := range n.children {
+		if child.hasDefault() {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *configInitNode) sortedChildNames() []string {
+	return sortedKeys(n.children)
+}
+
+// configInitIsNative reports whether values of type t can be written as bare YAML/JSON numbers or bools.
+// Everything else (including time.Duration, which is an int64 but is read from config as a string)
+// is written as a string.
+func configInitIsNative(t reflect.Type) bool {
+	if t == nil || t.Implements(reflect.TypeOf((*fmt.Stringer)(nil)).Elem()) {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// configInitFormat formats a value's string representation as a YAML/JSON value.
+// JSON strings are also valid YAML.
+func configInitFormat(s string, native bool) string {
+	if native {
+		return s
+	}
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+func newConfigInitLeaf(flagName string, fl Flag) *configInitLeaf {
+	val := fl.EmptyValueConstructor()
+	leaf := &configInitLeaf{
+		flagName:     flagName,
+		helpShort:    fl.HelpShort,
+		typeDesc:     val.Description(),
+		defaultVal:   "",
+		defaultElems: nil,
+	}
+	if !val.HasDefault() {
+		return leaf
+	}
+
+	switch v := val.(type) {
+	case value.ScalarValue:
+		leaf.defaultVal = configInitFormat(v.DefaultString(), configInitIsNative(reflect.TypeOf(v.Get())))
+	case value.SliceValue:
+		native := configInitIsNative(reflect.TypeOf(v.Get()).Elem())
+		for _, e := range v.DefaultStringSlice() {
+			leaf.defaultElems = append(leaf.defaultElems, configInitFormat(e, native))
+		}
+		leaf.defaultVal = "[" + strings.Join(leaf.defaultElems, ", ") + "]"
+	case value.DictValue:
+		native := configInitIsNative(reflect.TypeOf(v.Get()).Elem())
+		m := v.DefaultStringMap()
+		pairs := make([]string, 0, len(m))
+		for _, k := range sortedKeys(m) {
+			pairs = append(pairs, configInitFormat(k, false)+": "+configInitFormat(m[k], native))
+		}
+		leaf.defaultVal = "{" + strings.Join(pairs, ", ") + "}"
+	}
+	return leaf
+}
+
+// configPathFlag is a flag that declares a ConfigPath.
+type configPathFlag struct {
+	name string
+	flag Flag
+}
+
+// configPathFlags maps every ConfigPath declared by the global flags, section flags, and command flags to its flag.
+// When several flags share a ConfigPath, the first one found wins (global flags first, then sections and their commands breadth-first).
+// Set includeRenamed to also map the config paths flags were renamed from (see [RenamedFrom]).
+func (app *App) configPathFlags(includeRenamed bool) map[string]configPathFlag {
+	ret := make(map[string]configPathFlag)
+	add := func(fm FlagMap) {
+		for _, flagName := range fm.SortedNames() {
+			fl := fm[flagName]
+			configPaths := []string{fl.ConfigPath}
+			if includeRenamed {
+				for _, fr := range fl.RenamedFrom {
+					configPaths = append(configPaths, fr.ConfigPath)
+				}
+			}
+			for _, configPath := range configPaths {
+				if _, exists := ret[configPath]; configPath != "" && !exists {
+					ret[configPath] = configPathFlag{name: flagName, flag: fl}
+				}
+			}
+		}
+	}
+
+	add(app.GlobalFlags)
+	it := app.RootSection.breadthFirst([]string{app.Name})
+	for it.HasNext() {
+		flatSec := it.Next()
+		add(flatSec.Sec.Flags)
+		for _, cmdName := range flatSec.Sec.Cmds.SortedNames() {
+			add(flatSec.Sec.Cmds[cmdName].Flags)
+		}
+	}
+	return ret
+}
+
+// validateConfigPaths checks that no config path (including the ones flags were renamed from) is nested under another,
+// such as "db" and "db.url", since a config file can't hold both a value and a table at the same key.
+func (app *App) validateConfigPaths() error {
+	configPaths := app.configPathFlags(true)
+	// key[] paths nest like key paths, so compare without the []
+	keys := make(map[string]string, len(configPaths))
+	for configPath := range configPaths {
+		keys[strings.ReplaceAll(configPath, "[]", "")] = configPath
+	}
+	var errs []error
+	for _, configPath := range sortedKeys(configPaths) {
+		elems := strings.Split(strings.ReplaceAll(configPath, "[]", ""), ".")
+		for i := 1; i < len(elems); i++ {
+			if leafPath, exists := keys[strings.Join(elems[:i], ".")]; exists {
+				errs = append(errs, colerr.NewWrappedf(
+					nil,
+					"Config path is nested under another flag's config path: %s",
+					configPath+" ("+configPaths[configPath].name+") is under "+leafPath+" ("+configPaths[leafPath].name+")",
+				))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// configInitTree builds a tree of config paths from the global flags and every command's flags.
+func (app *App) configInitTree() *configInitNode {
+	root := newConfigInitNode()
+
+	for configPath, cpf := range app.configPathFlags(false) {
+		node := root
+		elems := strings.Split(configPath, ".")
+		for _, elem := range elems {
+			tableSlice := strings.HasSuffix(elem, "[]")
+			elem = strings.TrimSuffix(elem, "[]")
+			child, exists := node.children[elem]
+			if !exists {
+				child = newConfigInitNode()
+				child.tableSlice = tableSlice
+				node.children[elem] = child
+			}
+			node = child
+		}
+		node.leaf = newConfigInitLeaf(cpf.name, cpf.flag)
+	}
+	return root
+}
+
+// configInitYAMLComment returns the comment line describing a leaf.
+func configInitYAMLComment(leaf *configInitLeaf) string {
+	return fmt.Sprintf("# %s (%s, %s)", leaf.helpShort, leaf.typeDesc, leaf.flagName)
+}
+
+func configInitWriteYAML(w io.Writer, node *configInitNode, indent string, commented bool) {
+	for _, name := range node.sortedChildNames() {
+		child := node.children[name]
+		childCommented := commented || !child.hasDefault()
+		prefix := indent
+		if childCommented {
+			prefix += "# "
+		}
+
+		switch {
+		case child.leaf != nil:
+			fmt.Fprintln(w, indent+configInitYAMLComment(child.leaf))
+			if child.leaf.defaultVal == "" {
+				fmt.Fprintf(w, "%s%s:\n", prefix, name)
+			} else {
+				fmt.Fprintf(w, "%s%s: %s\n", prefix, name, child.leaf.defaultVal)
+			}
+		case child.tableSlice:
+			fmt.Fprintf(w, "%s%s:\n", prefix, name)
+			numElems := max(child.numElems(), 1)
+			for i := 0; i < numElems; i++ {
+				fmt.Fprintf(w, "%s  -\n", prefix)
+				for _, elemName := range child.sortedChildNames() {
+					leaf := child.children[elemName].leaf
+					if leaf == nil {
+						continue
+					}
+					elemIndent := indent + "    "
+					fmt.Fprintln(w, elemIndent+configInitYAMLComment(leaf))
+					if i < len(leaf.defaultElems) && !commented {
+						fmt.Fprintf(w, "%s%s: %s\n", elemIndent, elemName, leaf.defaultElems[i])
+					} else {
+						fmt.Fprintf(w, "%s# %s:\n", elemIndent, elemName)
+					}
+				}
+			}
+		default:
+			fmt.Fprintf(w, "%s%s:\n", prefix, name)
+			configInitWriteYAML(w, child, indent+"  ", childCommented)
+		}
+	}
+}
+
+// configInitWriteJSON writes the nodes with defaults as JSON object members (without the surrounding braces).
+func configInitWriteJSON(w io.Writer, node *configInitNode, indent string) {
+	names := []string{}
+	for _, name := range node.sortedChildNames() {
+		if node.children[name].hasDefault() {
+			names = append(names, name)
+		}
+	}
+	for i, name := range names {
+		child := node.children[name]
+		key := configInitFormat(name, false)
+		switch {
+		case child.leaf != nil:
+			fmt.Fprintf(w, "%s%s: %s", indent, key, child.leaf.defaultVal)
+		case child.tableSlice:
+			fmt.Fprintf(w, "%s%s: [\n", indent, key)
+			numElems := child.numElems()
+			for elemIdx := 0; elemIdx < numElems; elemIdx++ {
+				members := []string{}
+				for _, elemName := range child.sortedChildNames() {
+					leaf := child.children[elemName].leaf
+					if leaf != nil && elemIdx < len(leaf.defaultElems) {
+						members = append(members, fmt.Sprintf("%s    %s: %s", indent, configInitFormat(elemName, false), leaf.defaultElems[elemIdx]))
+					}
+				}
+				fmt.Fprintf(w, "%s  {\n%s\n%s  }", indent, strings.Join(members, ",\n"), indent)
+				if elemIdx < numElems-1 {
+					fmt.Fprint(w, ",")
+				}
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintf(w, "%s]", indent)
+		default:
+			fmt.Fprintf(w, "%s%s: {\n", indent, key)
+			configInitWriteJSON(w, child, indent+"  ")
+			fmt.Fprintf(w, "%s}", indent)
+		}
+		if i < len(names)-1 {
+			fmt.Fprint(w, ",")
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+func configInitCmdAction(cmdCtx CmdContext) error {
+	f := bufio.NewWriter(cmdCtx.Stdout)
+	defer f.Flush()
+
+	tree := cmdCtx.App.configInitTree()
+
+	format := cmdCtx.Flags["--format"].(string)
+	switch format {
+	case "yaml":
+		fmt.Fprintf(f, "# Config file for %s. Generated by '%s config init'.\n", cmdCtx.App.Name, cmdCtx.App.Name)
+		fmt.Fprintln(f, "# Flags without defaults are commented out.")
+		configInitWriteYAML(f, tree, "", false)
+	case "json":
+		fmt.Fprintln(f, "{")
+		configInitWriteJSON(f, tree, "  ")
+		fmt.Fprintln(f, "}")
+	default:
+		return colerr.NewWrappedf(nil, "unsupported config format: %s", format)
+	}
+	return nil
+}
+
+// configValidateUnknownKeys reports keys in iFace (found at configPath) that don't match or lead to a known config path.
+// Arrays of tables are checked with the key[] syntax.
+func configValidateUnknownKeys(configPath string, iFace interface{}, known map[string]configPathFlag) []string {
+	if _, exists := known[configPath]; exists {
+		return nil
+	}
+
+	isPrefix := func(prefix string) bool {
+		for p := range known {
+			if strings.HasPrefix(p, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch under := iFace.(type) {
+	case map[string]interface{}:
+		if !isPrefix(configPath + ".") {
+			break
+		}
+		unknown := []string{}
+		for _, k := range sortedKeys(under) {
+			unknown = append(unknown, configValidateUnknownKeys(configPath+"."+k, under[k], known)...)
+		}
+		return unknown
+	case []interface{}:
+		if !isPrefix(configPath + "[].") {
+			break
+		}
+		unknown := []string{}
+		for _, e := range under {
+			elem, ok := e.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for _, k := range sortedKeys(elem) {
+				for _, u := range configValidateUnknownKeys(configPath+"[]."+k, elem[k], known) {
+					if !slices.Contains(unknown, u) {
+						unknown = append(unknown, u)
+					}
+				}
+			}
+		}
+		return unknown
+	}
+	return []string{configPath}
+}
+
+// configValidateFile checks one config file, returning all problems found.
+func configValidateFile(reader config.Reader, known map[string]configPathFlag) []error {
+	problems := []error{}
+
+	if lister, ok := reader.(config.RootKeysLister); ok {
+		for _, k := range lister.RootKeys() {
+			res, err := reader.Search(k)
+			if err != nil {
+				problems = append(problems, colerr.NewWrappedf(err, "could not read key %s", k))
+				continue
+			}
+			for _, u := range configValidateUnknownKeys(k, res.IFace, known) {
+				problems = append(problems, errors.New("unknown key: "+u))
+			}
+		}
+	}
+
+	for _, configPath := range sortedKeys(known) {
+		cpf := known[configPath]
+		res, err := reader.Search(configPath)
+		if err != nil {
+			problems = append(problems, colerr.NewWrappedf(err, "could not read key %s (flag %s)", configPath, cpf.name))
+			continue
+		}
+		if res == nil {
+			continue
+		}
+		err = cpf.flag.EmptyValueConstructor().ReplaceFromInterface(res.IFace, value.UpdatedByConfig)
+		if err != nil {
+			problems = append(problems, colerr.NewWrappedf(
+				err,
+				"invalid value for key %s (flag %s): %s",
+				configPath, cpf.name, fmt.Sprintf("%#v", res.IFace),
+			))
+		}
+	}
+	return problems
+}
+
+func configValidateCmdAction(cmdCtx CmdContext) error {
+	app := cmdCtx.App
+
+	type configFileToValidate struct {
+		filePath  string
+		newReader config.NewReader
+		// mustExist is set for config flag values passed on the command line or from an env var
+		mustExist bool
+	}
+	files := []configFileToValidate{}
+	for _, cf := range app.ConfigFiles {
+		files = append(files, configFileToValidate{filePath: cf.FilePath, newReader: cf.NewReader, mustExist: false})
+	}
+	if app.ConfigFlagName != "" {
+		if configPath, exists := cmdCtx.Flags[app.ConfigFlagName]; exists {
+			updatedBy := cmdCtx.ParseState.FlagValues[app.ConfigFlagName].UpdatedBy()
+			files = append(files, configFileToValidate{
+				filePath:  configPath.(path.Path).String(),
+				newReader: app.NewConfigReader,
+				mustExist: updatedBy != value.UpdatedByDefault,
+			})
+		}
+	}
+	if len(files) == 0 {
+		return errors.New("no config files to validate")
+	}
+
+	s, err := conditionallyEnableStyle(false, cmdCtx.Flags, cmdCtx.Stderr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error enabling color. Continuing without: %v\n", err)
+	}
+
+	known := app.configPathFlags(true)
+	invalid := 0
+	for _, f := range files {
+		expanded, err := path.New(f.filePath).Expand()
+		if err != nil {
+			return colerr.NewWrappedf(err, "Error expanding config path ( %s ) ", f.filePath)
+		}
+		if _, err := os.Stat(expanded); err != nil {
+			if f.mustExist {
+				invalid++
+				colerr.Stacktrace(cmdCtx.Stderr, &s, colerr.NewWrappedf(err, "Config file passed with %s not found: %s", app.ConfigFlagName, f.filePath))
+				continue
+			}
+			fmt.Fprintf(cmdCtx.Stdout, "%s: not found, skipping\n", f.filePath)
+			continue
+		}
+		reader, err := f.newReader(expanded)
+		if err != nil {
+			invalid++
+			colerr.Stacktrace(cmdCtx.Stderr, &s, colerr.NewWrappedf(err, "Error reading config path ( %s ) ", f.filePath))
+			continue
+		}
+		if _, ok := reader.(config.RootKeysLister); !ok {
+			fmt.Fprintf(cmdCtx.Stdout, "%s: reader doesn't implement config.RootKeysLister, so unknown keys aren't checked\n", f.filePath)
+		}
+		problems := configValidateFile(reader, known)
+		if len(problems) > 0 {
+			invalid++
+			colerr.Stacktrace(cmdCtx.Stderr, &s, colerr.NewWrappedf(errors.Join(problems...), "Invalid config file: %s", f.filePath))
+			continue
+		}
+		fmt.Fprintf(cmdCtx.Stdout, "%s: ok\n", f.filePath)
+	}
+
+	if invalid > 0 {
+		return errors.New("config validation failed")
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+package warg_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"go.bbkane.com/warg"
+	"go.bbkane.com/warg/config/jsonreader"
+	"go.bbkane.com/warg/config/yamlreader"
+	"go.bbkane.com/warg/value/dict"
+	"go.bbkane.com/warg/value/scalar"
+	"go.bbkane.com/warg/value/slice"
+)
+
+func TestConfigInit(t *testing.T) {
+	updateGolden := os.Getenv("WARG_TEST_UPDATE_GOLDEN") != ""
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{
+			name: "yaml",
+			args: []string{"config", "init"},
+		},
+		{
+			name: "json",
+			args: []string{"config", "init", "--format", "json"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := warg.New(
+				"grabber",
+				"v1.0.0",
+				warg.NewSection(
+					"Grab images",
+					warg.NewSubCmd(
+						"grab",
+						"Grab images from subreddits",
+						warg.Unimplemented(),
+						warg.NewCmdFlag(
+							"--subreddit-name",
+							"Subreddits to grab",
+							slice.String(slice.Default([]string{"earthporn", "wallpapers"})),
+							warg.ConfigPath("subreddits[].name"),
+						),
+						warg.NewCmdFlag(
+							"--subreddit-limit",
+							"Max images per subreddit",
+							slice.Int(slice.Default([]int{5, 10})),
+							warg.ConfigPath("subreddits[].limit"),
+						),
+						warg.NewCmdFlag(
+							"--subreddit-destination",
+							"Where to store images",
+							slice.Path(),
+							warg.ConfigPath("subreddits[].destination"),
+						),
+						warg.NewCmdFlag(
+							"--timeout",
+							"Timeout for each download",
+							scalar.Duration(scalar.Default(30*time.Second)),
+							warg.ConfigPath("download.timeout"),
+						),
+						warg.NewCmdFlag(
+							"--headers",
+							"Extra headers",
+							dict.String(dict.Default(map[string]string{"Accept": "image/*"})),
+							warg.ConfigPath("download.headers"),
+						),
+						warg.NewCmdFlag(
+							"--not-in-config",
+							"Not in the config",
+							scalar.String(),
+						),
+					),
+					warg.NewSubSection(
+						"auth",
+						"Manage credentials",
+						warg.NewSubCmd(
+							"login",
+							"Log in",
+							warg.Unimplemented(),
+							warg.NewCmdFlag(
+								"--token",
+								"API token",
+								scalar.String(),
+								warg.ConfigPath("auth.token"),
+							),
+							warg.NewCmdFlag(
+								"--user",
+								"User name",
+								scalar.String(),
+								warg.ConfigPath("auth.user"),
+							),
+						),
+					),
+				),
+				warg.NewGlobalFlag(
+					"--verbose",
+					"Print more",
+					scalar.Bool(scalar.Default(false)),
+					warg.ConfigPath("verbose"),
+				),
+				warg.ConfigCmds(),
+				warg.SkipAll(),
+			)
+			warg.GoldenTest(
+				t,
+				warg.GoldenTestArgs{
+					App:             &app,
+					UpdateGolden:    updateGolden,
+					ExpectActionErr: false,
+					Args:            tt.args,
+				},
+				warg.ParseWithLookupEnv(warg.LookupMap(nil)),
+			)
+		})
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	updateGolden := os.Getenv("WARG_TEST_UPDATE_GOLDEN") != ""
+	tests := []struct {
+		name            string
+		args            []string
+		expectActionErr bool
+	}{
+		{
+			name:            "valid",
+			args:            []string{"config", "validate", "--config", "testdata/TestConfigValidate/valid/config.json"},
+			expectActionErr: false,
+		},
+		{
+			name:            "invalid",
+			args:            []string{"config", "validate", "--config", "testdata/TestConfigValidate/invalid/config.json"},
+			expectActionErr: true,
+		},
+		{
+			name:            "missing",
+			args:            []string{"config", "validate", "--config", "testdata/TestConfigValidate/missing/config.json"},
+			expectActionErr: true,
+		},
+		{
+			name:            "malformed",
+			args:            []string{"config", "validate", "--config", "testdata/TestConfigValidate/malformed/config.json"},
+			expectActionErr: true,
+		},
+		{
+			name:            "invalidGlobalValue",
+			args:            []string{"config", "validate", "--config", "testdata/TestConfigValidate/invalidGlobalValue/config.json"},
+			expectActionErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := warg.New(
+				"grabber",
+				"v1.0.0",
+				warg.NewSection(
+					"Grab images",
+					warg.NewSubCmd(
+						"grab",
+						"Grab images",
+						warg.Unimplemented(),
+						warg.NewCmdFlag(
+							"--timeout",
+							"Timeout for each download",
+							scalar.Duration(),
+							warg.ConfigPath("download.timeout"),
+						),
+						warg.NewCmdFlag(
+							"--retries",
+							"Retries for each download",
+							scalar.Int(),
+							warg.ConfigPath("download.retries"),
+						),
+						warg.NewCmdFlag(
+							"--headers",
+							"Extra headers",
+							dict.String(),
+							warg.ConfigPath("download.headers"),
+						),
+					),
+				),
+				warg.NewGlobalFlag(
+					"--verbose",
+					"Print more",
+					scalar.Bool(),
+					warg.ConfigPath("verbose"),
+				),
+				warg.ConfigFile(yamlreader.New, "testdata/TestConfigValidate/base.yaml"),
+				warg.ConfigFlag(
+					jsonreader.New,
+					warg.FlagMap{
+						"--config": warg.NewFlag(
+							"Path to config file",
+							scalar.Path(),
+						),
+					},
+				),
+				warg.ConfigCmds(),
+				warg.SkipAll(),
+			)
+			warg.GoldenTest(
+				t,
+				warg.GoldenTestArgs{
+					App:             &app,
+					UpdateGolden:    updateGolden,
+					ExpectActionErr: tt.expectActionErr,
+					Args:            tt.args,
+				},
+				warg.ParseWithLookupEnv(warg.LookupMap(nil)),
+			)
+		})
+	}
+}
+
+func TestConfigSchema(t *testing.T) {
+	updateGolden := os.Getenv("WARG_TEST_UPDATE_GOLDEN") != ""
+	app := warg.New(
+		"grabber",
+		"v1.0.0",
+		warg.NewSection(
+			"Grab images",
+			warg.NewSubCmd(
+				"grab",
+				"Grab images from subreddits",
+				warg.Unimplemented(),
+				warg.NewCmdFlag(
+					"--subreddit-name",
+					"Subreddits to grab",
+					slice.String(slice.Default([]string{"earthporn", "wallpapers"})),
+					warg.ConfigPath("subreddits[].name"),
+				),
+				warg.NewCmdFlag(
+					"--subreddit-limit",
+					"Max images per subreddit",
+					slice.Int(slice.Choices(5, 10, 20)),
+					warg.ConfigPath("subreddits[].limit"),
+				),
+				warg.NewCmdFlag(
+					"--timeout",
+					"Timeout for each download",
+					scalar.Duration(scalar.Default(30*time.Second)),
+					warg.ConfigPath("download.timeout"),
+				),
+				warg.NewCmdFlag(
+					"--headers",
+					"Extra headers",
+					dict.String(dict.Default(map[string]string{"Accept": "image/*"})),
+					warg.ConfigPath("download.headers"),
+				),
+				warg.NewCmdFlag(
+					"--retries",
+					"Retries per image",
+					scalar.Int(scalar.Choices(0, 1, 3), scalar.Default(1)),
+					warg.ConfigPath("download.retries"),
+					warg.RenamedFrom("--tries", warg.OldConfigPath("download.tries")),
+				),
+				warg.NewCmdFlag(
+					"--formats",
+					"Image formats to keep",
+					slice.String(slice.Choices("jpg", "png")),
+					warg.ConfigPath("download.formats"),
+					warg.FlagDeprecated("keep every format"),
+				),
+				warg.NewCmdFlag(
+					"--scale",
+					"Scale factor",
+					scalar.Float64(),
+					warg.ConfigPath("download.scale"),
+				),
+				warg.NewCmdFlag(
+					"--not-in-config",
+					"Not in the config",
+					scalar.String(),
+				),
+			),
+		),
+		warg.NewGlobalFlag(
+			"--verbose",
+			"Print more",
+			scalar.Bool(scalar.Default(false)),
+			warg.ConfigPath("verbose"),
+		),
+		warg.ConfigCmds(),
+		warg.SkipAll(),
+	)
+	warg.GoldenTest(
+		t,
+		warg.GoldenTestArgs{
+			App:             &app,
+			UpdateGolden:    updateGolden,
+			ExpectActionErr: false,
+			Args:            []string{"config", "schema"},
+		},
+		warg.ParseWithLookupEnv(warg.LookupMap(nil)),
+	)
+}
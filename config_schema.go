@@ -0,0 +1,167 @@
+package warg
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"go.bbkane.com/warg/colerr"
+	"go.bbkane.com/warg/value"
+)
+
+// configSchema is a JSON Schema (draft 2020-12) node. Only the keywords config schemas need are included.
+type configSchema struct {
+	Schema      string `json:"$schema,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type,omitempty"`
+	// Properties are the members of an object
+	Properties map[string]*configSchema `json:"properties,omitempty"`
+	// AdditionalProperties is false for objects built from config paths, and the value schema for dicts
+	AdditionalProperties any               `json:"additionalProperties,omitempty"`
+	Items                *configSchema     `json:"items,omitempty"`
+	Enum                 []json.RawMessage `json:"enum,omitempty"`
+	Default              json.RawMessage   `json:"default,omitempty"`
+	Deprecated           bool              `json:"deprecated,omitempty"`
+}
+
+func newConfigSchema(typ string) *configSchema {
+	return &configSchema{
+		Schema:               "",
+		Title:                "",
+		Description:          "",
+		Type:                 typ,
+		Properties:           nil,
+		AdditionalProperties: nil,
+		Items:                nil,
+		Enum:                 nil,
+		Default:              nil,
+		Deprecated:           false,
+	}
+}
+
+// newConfigSchemaObject returns an object schema that only allows the properties added to it,
+// matching "config validate"'s unknown key check.
+func newConfigSchemaObject() *configSchema {
+	s := newConfigSchema("object")
+	s.Properties = make(map[string]*configSchema)
+	s.AdditionalProperties = false
+	return s
+}
+
+// configSchemaType returns the JSON Schema type for values of Go type t. Like "config init",
+// only numbers and bools are native; everything else (including time.Duration) is read from a string.
+func configSchemaType(t reflect.Type) string {
+	if !configInitIsNative(t) {
+		return "string"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	default:
+		return "integer"
+	}
+}
+
+// configSchemaElem returns the schema for one element of a value of Go type t, with an enum from choices.
+func configSchemaElem(t reflect.Type, choices []string) *configSchema {
+	s := newConfigSchema(configSchemaType(t))
+	native := configInitIsNative(t)
+	for _, choice := range choices {
+		s.Enum = append(s.Enum, json.RawMessage(configInitFormat(choice, native)))
+	}
+	return s
+}
+
+// configSchemaLeaf returns the schema for a flag's value. inTableSlice means the config path has a
+// key[] element, so each element of the (slice) flag's value is in a different table.
+func configSchemaLeaf(flagName string, fl Flag, inTableSlice bool, deprecated bool) *configSchema {
+	val := fl.EmptyValueConstructor()
+	var s *configSchema
+	switch v := val.(type) {
+	case value.SliceValue:
+		elem := configSchemaElem(reflect.TypeOf(v.Get()).Elem(), val.Choices())
+		if inTableSlice {
+			s = elem
+		} else {
+			s = newConfigSchema("array")
+			s.Items = elem
+		}
+	case value.DictValue:
+		s = newConfigSchema("object")
+		s.AdditionalProperties = configSchemaElem(reflect.TypeOf(v.Get()).Elem(), val.Choices())
+	default:
+		s = configSchemaElem(reflect.TypeOf(val.Get()), val.Choices())
+	}
+
+	s.Description = fl.HelpShort + " (" + flagName + ")"
+	// defaults for key[] paths are spread across tables, so they can't be set on one element
+	if leaf := newConfigInitLeaf(flagName, fl); leaf.defaultVal != "" && !inTableSlice {
+		s.Default = json.RawMessage(leaf.defaultVal)
+	}
+	s.Deprecated = deprecated || fl.Deprecation != nil
+	return s
+}
+
+// ConfigJSONSchema returns a JSON Schema (draft 2020-12) describing the app's config file, built from every
+// flag's ConfigPath and value type. Dotted paths become nested objects, key[] paths become arrays of objects,
+// slices become arrays, dicts become objects with additionalProperties, and choices become enums.
+// Config paths flags were renamed from (see [RenamedFrom]) are marked deprecated.
+// Editors can use it to validate and complete config files, such as YAML files with a
+// "# yaml-language-server: $schema=<path>" comment.
+func (app *App) ConfigJSONSchema() ([]byte, error) {
+	root := newConfigSchemaObject()
+	root.Schema = "https://json-schema.org/draft/2020-12/schema"
+	root.Title = app.Name + " config"
+
+	configPaths := app.configPathFlags(true)
+	for _, configPath := range sortedKeys(configPaths) {
+		cpf := configPaths[configPath]
+		node := root
+		inTableSlice := false
+		elems := strings.Split(configPath, ".")
+		for _, elem := range elems[:len(elems)-1] {
+			tableSlice := strings.HasSuffix(elem, "[]")
+			name := strings.TrimSuffix(elem, "[]")
+			child, exists := node.Properties[name]
+			// App.Validate rejects config paths nested under other config paths, so child is never a flag's schema
+			if !exists {
+				child = newConfigSchemaObject()
+				if tableSlice {
+					items := child
+					child = newConfigSchema("array")
+					child.Items = items
+				}
+				node.Properties[name] = child
+			}
+			if child.Items != nil {
+				child = child.Items
+				inTableSlice = true
+			}
+			node = child
+		}
+		name := strings.TrimSuffix(elems[len(elems)-1], "[]")
+		deprecated := cpf.flag.ConfigPath != configPath
+		node.Properties[name] = configSchemaLeaf(cpf.name, cpf.flag, inTableSlice, deprecated)
+	}
+
+	b, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, colerr.NewWrapped(err, "Could not marshal config JSON Schema")
+	}
+	return b, nil
+}
+
+func configSchemaCmdAction(cmdCtx CmdContext) error {
+	b, err := cmdCtx.App.ConfigJSONSchema()
+	if err != nil {
+		return err
+	}
+	_, err = cmdCtx.Stdout.Write(append(b, '\n'))
+	if err != nil {
+		return colerr.NewWrapped(err, "Could not write config JSON Schema")
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package warg
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"go.bbkane.com/warg/colerr"
+)
+
+// Deprecation marks a [Flag], [Cmd], or [Section] as deprecated. Deprecated items still parse,
+// but using them prints a warning to [CmdContext].Stderr.
+type Deprecation struct {
+	// Message tells the user what to do instead, such as "use --output instead"
+	Message string
+
+	// RemovedIn is the app version the item will be removed in. Optional.
+	// See [ValidateDeprecationRemovals].
+	RemovedIn string
+}
+
+// DeprecationOpt is a functional option for configuring a [Deprecation].
+type DeprecationOpt func(*Deprecation)
+
+// RemovedIn records the app version a deprecated item will be removed in.
+func RemovedIn(version string) DeprecationOpt {
+	return func(d *Deprecation) {
+		d.RemovedIn = version
+	}
+}
+
+func newDeprecation(msg string, opts ...DeprecationOpt) *Deprecation {
+	d := Deprecation{
+		Message:   msg,
+		RemovedIn: "",
+	}
+	for _, opt := range opts {
+		opt(&d)
+	}
+	return &d
+}
+
+// FlagDeprecated marks the flag as deprecated. msg should tell the user what to use instead.
+//
+// Example usage:
+//
+//	warg.FlagDeprecated("use --output instead", warg.RemovedIn("v2.0.0"))
+func FlagDeprecated(msg string, opts ...DeprecationOpt) FlagOpt {
+	return func(f *Flag) {
+		f.Deprecation = newDeprecation(msg, opts...)
+	}
+}
+
+// FlagHidden omits the flag from help and completions. It can still be passed.
+func FlagHidden() FlagOpt {
+	return func(f *Flag) {
+		f.Hidden = true
+	}
+}
+
+// CmdDeprecated marks the command as deprecated. msg should tell the user what to use instead.
+func CmdDeprecated(msg string, opts ...DeprecationOpt) CmdOpt {
+	return func(cmd *Cmd) {
+		cmd.Deprecation = newDeprecation(msg, opts...)
+	}
+}
+
+// CmdHidden omits the command from help and completions. It can still be run.
+func CmdHidden() CmdOpt {
+	return func(cmd *Cmd) {
+		cmd.Hidden = true
+	}
+}
+
+// SectionDeprecated marks the section (and so all commands under it) as deprecated. msg should tell the user what to use instead.
+func SectionDeprecated(msg string, opts ...DeprecationOpt) SectionOpt {
+	return func(sec *Section) {
+		sec.Deprecation = newDeprecation(msg, opts...)
+	}
+}
+
+// SectionHidden omits the section (and everything under it) from help and completions. It can still be used.
+func SectionHidden() SectionOpt {
+	return func(sec *Section) {
+		sec.Hidden = true
+	}
+}
+
+// ValidateDeprecationRemovals makes [App.Validate] fail if a deprecated item's RemovedIn version
+// is less than or equal to the app version, so deprecated items aren't forgotten. Versions that
+// don't look like "v1.2.3" (such as "(devel)") aren't compared.
+func ValidateDeprecationRemovals() AppOpt {
+	return func(a *App) {
+		a.ValidateDeprecationRemovals = true
+	}
+}
+
+// deprecatedHelpSuffix returns " [deprecated: msg]" for deprecated items and "" for others.
+func deprecatedHelpSuffix(d *Deprecation) string {
+	if d == nil {
+		return ""
+	}
+	return " [deprecated: " + d.Message + "]"
+}
+
+// deprecationWarnings lists a warning for each deprecated section in the path, the deprecated
+// current command, and each deprecated flag the user set (from any source but its default).
+func (app *App) deprecationWarnings(ps *ParseState) []string {
+	var warnings []string
+	sec := app.RootSection
+	for _, name := range ps.SectionPath {
+		sec = sec.Sections[name]
+		if sec.Deprecation != nil {
+			warnings = append(warnings, "section "+name+" is deprecated: "+sec.Deprecation.Message)
+		}
+	}
+	if ps.CurrentCmd != nil && ps.CurrentCmd.Deprecation != nil {
+		warnings = append(warnings, "command "+ps.CurrentCmdName+" is deprecated: "+ps.CurrentCmd.Deprecation.Message)
+	}
+	for _, fm := range []FlagMap{app.GlobalFlags, ps.CurrentCmd.Flags} {
+		for _, name := range fm.SortedNames() {
+			fl := fm[name]
+			if fl.Deprecation != nil && flagSetByUser(ps.FlagValues, name) {
+				warnings = append(warnings, "flag "+describeFlagSource(name, ps.FlagSources)+" is deprecated: "+fl.Deprecation.Message)
+			}
+		}
+	}
+	return warnings
+}
+
+// warnDeprecated prints a styled warning to cmdCtx.Stderr for each deprecated item used.
+func warnDeprecated(cmdCtx CmdContext) {
+	warnings := cmdCtx.App.deprecationWarnings(cmdCtx.ParseState)
+	if len(warnings) == 0 || cmdCtx.Stderr == nil {
+		return
+	}
+	s, err := conditionallyEnableStyle(false, cmdCtx.Flags, cmdCtx.Stderr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error enabling color. Continuing without: %v\n", err)
+	}
+	for _, w := range warnings {
+		fmt.Fprintln(cmdCtx.Stderr, s.ErrorAlt("Warning")+": "+w)
+	}
+}
+
+// parseVersion parses versions like "v1.2.3", "1.2", or "v1.2.3-rc1" into their numeric parts,
+// ignoring any pre-release or build suffix.
+func parseVersion(version string) ([]int, bool) {
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	if version == "" {
+		return nil, false
+	}
+	var parts []int
+	for _, p := range strings.Split(version, ".") {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, false
+		}
+		parts = append(parts, n)
+	}
+	return parts, true
+}
+
+// compareVersions compares versions a and b like [strings.Compare]. ok is false if either can't be parsed.
+func compareVersions(a string, b string) (int, bool) {
+	aParts, aOK := parseVersion(a)
+	bParts, bOK := parseVersion(b)
+	if !aOK || !bOK {
+		return 0, false
+	}
+	for i := 0; i < max(len(aParts), len(bParts)); i++ {
+		var aN, bN int
+		if i < len(aParts) {
+			aN = aParts[i]
+		}
+		if i < len(bParts) {
+			bN = bParts[i]
+		}
+		if aN != bN {
+			if aN < bN {
+				return -1, true
+			}
+			return 1, true
+		}
+	}
+	return 0, true
+}
+
+// validateDeprecationRemovals returns an error for each deprecated item that should have been removed by the app's version.
+func (app *App) validateDeprecationRemovals() error {
+	var errs []error
+	check := func(kind string, name string, d *Deprecation) {
+		if d == nil || d.RemovedIn == "" {
+			return
+		}
+		if _, ok := parseVersion(d.RemovedIn); !ok {
+			errs = append(errs, colerr.NewWrappedf(nil, "Invalid RemovedIn version for deprecated %s: %s", kind, name+": "+d.RemovedIn))
+			return
+		}
+		cmp, ok := compareVersions(app.Version, d.RemovedIn)
+		if ok && cmp >= 0 {
+			errs = append(errs, colerr.NewWrappedf(
+				nil,
+				"Deprecated %s should have been removed in %s (app version is %s): %s",
+				kind,
+				d.RemovedIn,
+				app.Version,
+				name,
+			))
+		}
+	}
+
+	for _, name := range app.GlobalFlags.SortedNames() {
+		check("flag", name, app.GlobalFlags[name].Deprecation)
+	}
+	it := app.RootSection.breadthFirst(nil)
+	for it.HasNext() {
+		flatSec := it.Next()
+		if len(flatSec.Path) > 0 {
+			check("section", strings.Join(flatSec.Path, " "), flatSec.Sec.Deprecation)
+		}
+		for _, flagName := range flatSec.Sec.Flags.SortedNames() {
+			check("flag", strings.Join(append(append([]string(nil), flatSec.Path...), flagName), " "), flatSec.Sec.Flags[flagName].Deprecation)
+		}
+		for _, cmdName := range flatSec.Sec.Cmds.SortedNames() {
+			cmd := flatSec.Sec.Cmds[cmdName]
+			cmdPath := strings.Join(append(append([]string(nil), flatSec.Path...), cmdName), " ")
+			check("command", cmdPath, cmd.Deprecation)
+			for _, flagName := range cmd.Flags.SortedNames() {
+				check("flag", cmdPath+" "+flagName, cmd.Flags[flagName].Deprecation)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
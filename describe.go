@@ -0,0 +1,362 @@
+package warg
+
+import (
+	"slices"
+	"strings"
+
+	"go.bbkane.com/warg/value"
+)
+
+// DescriptionSchemaVersion is the version of the [AppDescription] format. It's incremented when a
+// field is removed or changes meaning. Adding fields doesn't change it, so readers should ignore fields they don't know.
+const DescriptionSchemaVersion = 1
+
+// AppDescription is a machine-readable description of an app's command line interface, for tools like
+// wrappers, GUIs, and LLM agents that introspect the command tree. Create it with [App.Describe] or
+// print it as JSON with "--help json". Hidden sections, commands, and flags are omitted.
+// Sections, commands, flags, and choices are sorted by name, so the JSON is stable enough to commit and diff.
+type AppDescription struct {
+	// SchemaVersion is [DescriptionSchemaVersion] when the description was created
+	SchemaVersion int    `json:"schemaVersion"`
+	Name          string `json:"name"`
+	Version       string `json:"version"`
+	// GlobalFlags are accepted by every command
+	GlobalFlags []FlagDescription `json:"globalFlags"`
+	// RootSection holds the app's commands and sections. Its Name is empty.
+	RootSection SectionDescription `json:"rootSection"`
+}
+
+// DeprecationDescription describes a [Deprecation].
+type DeprecationDescription struct {
+	Message   string `json:"message"`
+	RemovedIn string `json:"removedIn,omitempty"`
+}
+
+// SectionDescription describes a [Section].
+type SectionDescription struct {
+	Name        string                  `json:"name"`
+	Aliases     []string                `json:"aliases,omitempty"`
+	HelpShort   string                  `json:"helpShort"`
+	HelpLong    string                  `json:"helpLong,omitempty"`
+	Footer      string                  `json:"footer,omitempty"`
+	Deprecation *DeprecationDescription `json:"deprecation,omitempty"`
+	// Flags are accepted by every command in the section and its subsections (see [NewSectionFlag])
+	Flags    []FlagDescription    `json:"flags,omitempty"`
+	Cmds     []CmdDescription     `json:"cmds,omitempty"`
+	Sections []SectionDescription `json:"sections,omitempty"`
+}
+
+// CmdDescription describes a [Cmd]. Flags inherited from sections are described on the sections.
+type CmdDescription struct {
+	Name        string                  `json:"name"`
+	Aliases     []string                `json:"aliases,omitempty"`
+	HelpShort   string                  `json:"helpShort"`
+	HelpLong    string                  `json:"helpLong,omitempty"`
+	Footer      string                  `json:"footer,omitempty"`
+	Deprecation *DeprecationDescription `json:"deprecation,omitempty"`
+	Flags       []FlagDescription       `json:"flags,omitempty"`
+	// Positionals are in the order they must be passed
+	Positionals []PositionalDescription `json:"positionals,omitempty"`
+	// AllowForwardedArgs means extra args are accepted after "--"
+	AllowForwardedArgs bool `json:"allowForwardedArgs"`
+	// Constraints describe cross-flag relationships (see [FlagConstraint] and [FlagRule]), such as "--a and --b are mutually exclusive"
+	Constraints []string `json:"constraints,omitempty"`
+}
+
+// ValueDescription describes the [value.Value] a flag or positional holds.
+type ValueDescription struct {
+	// Type is from [value.Value.Description], such as "int" or "[]string"
+	Type string `json:"type"`
+	// Kind is "scalar", "slice", or "dict". Slice flags can be passed multiple times.
+	Kind    string   `json:"kind"`
+	Choices []string `json:"choices,omitempty"`
+	// Constraints are from [value.Constraints], such as "min: 1"
+	Constraints []string `json:"constraints,omitempty"`
+	// HasDefault is true if the value has a default, even an empty one such as "". The default is in
+	// the field for the value's Kind; an empty default is omitted from that field.
+	HasDefault bool `json:"hasDefault"`
+	// Default is a scalar value's default
+	Default string `json:"default,omitempty"`
+	// DefaultSlice is a slice value's default
+	DefaultSlice []string `json:"defaultSlice,omitempty"`
+	// DefaultDict is a dict value's default
+	DefaultDict map[string]string `json:"defaultDict,omitempty"`
+}
+
+// FlagDescription describes a [Flag].
+type FlagDescription struct {
+	Name string `json:"name"`
+	// Aliases are shown in help, such as "-v"
+	Aliases []string `json:"aliases,omitempty"`
+	// RenamedFrom are old names that still work (see [RenamedFrom])
+	RenamedFrom []string `json:"renamedFrom,omitempty"`
+	HelpShort   string   `json:"helpShort"`
+	Group       string   `json:"group,omitempty"`
+	ValueDescription
+	// EnvVars are looked up in order, including names derived from [EnvPrefix]
+	EnvVars    []string `json:"envVars,omitempty"`
+	ConfigPath string   `json:"configPath,omitempty"`
+	// OldConfigPaths are config paths the flag was renamed from that are still read (see [OldConfigPath])
+	OldConfigPaths []string                `json:"oldConfigPaths,omitempty"`
+	Required       bool                    `json:"required"`
+	Switch         bool                    `json:"switch"`
+	Negatable      bool                    `json:"negatable"`
+	Deprecation    *DeprecationDescription `json:"deprecation,omitempty"`
+}
+
+// PositionalDescription describes a [Positional].
+type PositionalDescription struct {
+	Name      string `json:"name"`
+	HelpShort string `json:"helpShort"`
+	ValueDescription
+	Required bool `json:"required"`
+	Variadic bool `json:"variadic"`
+}
+
+// Describe returns a machine-readable description of the app's command line interface.
+// Marshal it to JSON with [encoding/json].
+func (app *App) Describe() AppDescription {
+	return AppDescription{
+		SchemaVersion: DescriptionSchemaVersion,
+		Name:          app.Name,
+		Version:       app.Version,
+		GlobalFlags:   app.describeFlags(app.GlobalFlags, nil),
+		RootSection:   app.describeSection("", nil, app.RootSection),
+	}
+}
+
+func describeDeprecation(d *Deprecation) *DeprecationDescription {
+	if d == nil {
+		return nil
+	}
+	return &DeprecationDescription{Message: d.Message, RemovedIn: d.RemovedIn}
+}
+
+func (app *App) describeSection(name string, path []string, sec Section) SectionDescription {
+	desc := SectionDescription{
+		Name:        name,
+		Aliases:     slices.Clone(sec.Aliases),
+		HelpShort:   sec.HelpShort,
+		HelpLong:    sec.HelpLong,
+		Footer:      sec.Footer,
+		Deprecation: describeDeprecation(sec.Deprecation),
+		Flags:       app.describeFlags(sec.Flags, path),
+		Cmds:        nil,
+		Sections:    nil,
+	}
+	for _, cmdName := range sec.Cmds.visibleSortedNames() {
+		cmdPath := append(slices.Clone(path), cmdName)
+		desc.Cmds = append(desc.Cmds, app.describeCmd(cmdName, cmdPath, sec.Cmds[cmdName]))
+	}
+	for _, secName := range sec.Sections.visibleSortedNames() {
+		secPath := append(slices.Clone(path), secName)
+		desc.Sections = append(desc.Sections, app.describeSection(secName, secPath, sec.Sections[secName]))
+	}
+	return desc
+}
+
+func (app *App) describeCmd(name string, cmdPath []string, cmd Cmd) CmdDescription {
+	desc := CmdDescription{
+		Name:               name,
+		Aliases:            slices.Clone(cmd.Aliases),
+		HelpShort:          cmd.HelpShort,
+		HelpLong:           cmd.HelpLong,
+		Footer:             cmd.Footer,
+		Deprecation:        describeDeprecation(cmd.Deprecation),
+		Flags:              app.describeFlags(cmd.Flags, cmdPath),
+		Positionals:        nil,
+		AllowForwardedArgs: cmd.AllowForwardedArgs,
+		Constraints:        nil,
+	}
+	for _, pos := range cmd.Positionals {
+		desc.Positionals = append(desc.Positionals, PositionalDescription{
+			Name:             pos.Name,
+			HelpShort:        pos.HelpShort,
+			ValueDescription: describeValue(pos.EmptyValueConstructor()),
+			Required:         pos.Required,
+			Variadic:         pos.Variadic,
+		})
+	}
+	for _, fc := range cmd.Constraints {
+		desc.Constraints = append(desc.Constraints, fc.String())
+	}
+	for _, fr := range cmd.Rules {
+		desc.Constraints = append(desc.Constraints, fr.String())
+	}
+	return desc
+}
+
+// describeFlags describes the visible flags in fm, sorted by name. envPath is the command or
+// section path used to derive env var names (see [EnvPrefix]).
+func (app *App) describeFlags(fm FlagMap, envPath []string) []FlagDescription {
+	var descs []FlagDescription
+	for _, name := range fm.visibleSortedNames() {
+		fl := fm[name]
+		var renamedFrom []string
+		var oldConfigPaths []string
+		for _, fr := range fl.RenamedFrom {
+			renamedFrom = append(renamedFrom, fr.Name)
+			if fr.ConfigPath != "" {
+				oldConfigPaths = append(oldConfigPaths, fr.ConfigPath)
+			}
+		}
+		descs = append(descs, FlagDescription{
+			Name:             name,
+			Aliases:          fl.helpAliases(),
+			RenamedFrom:      renamedFrom,
+			HelpShort:        fl.HelpShort,
+			Group:            fl.Group,
+			ValueDescription: describeValue(fl.EmptyValueConstructor()),
+			EnvVars:          app.flagEnvVars(name, fl, envPath),
+			ConfigPath:       fl.ConfigPath,
+			OldConfigPaths:   oldConfigPaths,
+			Required:         fl.Required,
+			Switch:           fl.Switch,
+			Negatable:        fl.Negatable,
+			Deprecation:      describeDeprecation(fl.Deprecation),
+		})
+	}
+	return descs
+}
+
+func describeValue(val value.Value) ValueDescription {
+	desc := ValueDescription{
+		Type:         val.Description(),
+		Kind:         "",
+		Choices:      val.Choices(),
+		Constraints:  value.Constraints(val),
+		HasDefault:   val.HasDefault(),
+		Default:      "",
+		DefaultSlice: nil,
+		DefaultDict:  nil,
+	}
+	switch v := val.(type) {
+	case value.ScalarValue:
+		desc.Kind = "scalar"
+		if v.HasDefault() {
+			desc.Default = v.DefaultString()
+		}
+	case value.SliceValue:
+		desc.Kind = "slice"
+		if v.HasDefault() {
+			desc.DefaultSlice = v.DefaultStringSlice()
+		}
+	case value.DictValue:
+		desc.Kind = "dict"
+		if v.HasDefault() {
+			desc.DefaultDict = v.DefaultStringMap()
+		}
+	}
+	return desc
+}
+
+// describedSection is a section from an [AppDescription] along with the sections containing it.
+// Docs and man pages are rendered from these, so they show the same data as "--help json".
+type describedSection struct {
+	// Path is the section path, not including the app name. It's empty for the root section.
+	Path []string
+	// Chain holds the root section, then each section in Path, so Chain[i]'s path is Path[:i] and the last element is this section.
+	Chain []SectionDescription
+}
+
+// Sec returns the section itself.
+func (ds describedSection) Sec() SectionDescription {
+	return ds.Chain[len(ds.Chain)-1]
+}
+
+// ChildPath returns the path of the command or section childName in the section.
+func (ds describedSection) ChildPath(childName string) []string {
+	return append(slices.Clone(ds.Path), childName)
+}
+
+// describedFlags are flags declared by the section at Path.
+type describedFlags struct {
+	Path  []string
+	Flags []FlagDescription
+}
+
+// InheritedFlags returns the flags commands in the section inherit from it and the sections containing it, nearest section first.
+func (ds describedSection) InheritedFlags() []describedFlags {
+	var ret []describedFlags
+	for i := len(ds.Chain) - 1; i >= 0; i-- {
+		if len(ds.Chain[i].Flags) > 0 {
+			ret = append(ret, describedFlags{Path: slices.Clone(ds.Path[:i]), Flags: ds.Chain[i].Flags})
+		}
+	}
+	return ret
+}
+
+// section returns the section at path along with the sections containing it. path must name visible
+// sections, such as a path from depthFirstSections (section.go), which docs and man pages walk in help order.
+func (desc AppDescription) section(path []string) describedSection {
+	chain := []SectionDescription{desc.RootSection}
+	for _, name := range path {
+		children := chain[len(chain)-1].Sections
+		i := slices.IndexFunc(children, func(sd SectionDescription) bool { return sd.Name == name })
+		chain = append(chain, children[i])
+	}
+	return describedSection{Path: path, Chain: chain}
+}
+
+// describedFlagGroup is a [FlagGroup] heading and its flags. Name is "" for flags without a group.
+type describedFlagGroup struct {
+	Name  string
+	Flags []FlagDescription
+}
+
+// groupFlags groups flags (sorted by name) the way help does: flags without a group first, then each group by name.
+func groupFlags(flags []FlagDescription) []describedFlagGroup {
+	groups := make(map[string][]FlagDescription)
+	for _, fd := range flags {
+		groups[fd.Group] = append(groups[fd.Group], fd)
+	}
+	var ret []describedFlagGroup
+	if ungrouped, exists := groups[""]; exists {
+		ret = append(ret, describedFlagGroup{Name: "", Flags: ungrouped})
+	}
+	for _, name := range sortedKeys(groups) {
+		if name != "" {
+			ret = append(ret, describedFlagGroup{Name: name, Flags: groups[name]})
+		}
+	}
+	return ret
+}
+
+// helpSuffix returns " [deprecated: msg]" if d isn't nil, like [deprecatedHelpSuffix].
+func (d *DeprecationDescription) helpSuffix() string {
+	if d == nil {
+		return ""
+	}
+	return " [deprecated: " + d.Message + "]"
+}
+
+// positionalsUsage returns positionals formatted for a usage line, such as "SRC [DST...]".
+func positionalsUsage(positionals []PositionalDescription) string {
+	names := make([]string, 0, len(positionals))
+	for _, pos := range positionals {
+		names = append(names, positionalUsage(pos.Name, pos.Required, pos.Variadic))
+	}
+	return strings.Join(names, " ")
+}
+
+// defaultString formats a [ValueDescription] default on one line: slice elements and dict key=value
+// pairs are joined with ", ". ok is false if there is no default.
+func (vd ValueDescription) defaultString() (string, bool) {
+	if !vd.HasDefault {
+		return "", false
+	}
+	switch vd.Kind {
+	case "scalar":
+		return vd.Default, true
+	case "slice":
+		return strings.Join(vd.DefaultSlice, ", "), true
+	case "dict":
+		pairs := make([]string, 0, len(vd.DefaultDict))
+		for _, key := range sortedKeys(vd.DefaultDict) {
+			pairs = append(pairs, key+"="+vd.DefaultDict[key])
+		}
+		return strings.Join(pairs, ", "), true
+	default:
+		return "", false
+	}
+}
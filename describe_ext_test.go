@@ -0,0 +1,119 @@
+package warg_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.bbkane.com/warg"
+	"go.bbkane.com/warg/value/dict"
+	"go.bbkane.com/warg/value/scalar"
+	"go.bbkane.com/warg/value/slice"
+)
+
+func describeTestApp() warg.App {
+	return warg.New(
+		"myapp",
+		"v1.0.0",
+		warg.NewSection(
+			"Manage my app",
+			warg.NewSubCmd(
+				"copy",
+				"Copy files",
+				warg.Unimplemented(),
+				warg.CmdAliases("cp"),
+				warg.CmdHelpLong("Copy files from SRC to DST."),
+				warg.CmdPositional("SRC", "Source file", scalar.Path(), warg.PositionalRequired()),
+				warg.CmdPositional("DST", "Destination files", slice.String(slice.Default([]string{"out.txt"})), warg.PositionalVariadic()),
+				warg.NewCmdFlag(
+					"--mode",
+					"Copy mode",
+					scalar.String(scalar.Choices("fast", "safe"), scalar.Default("safe")),
+					warg.ConfigPath("copy.mode"),
+					warg.FlagGroup("Behavior"),
+				),
+				warg.NewCmdFlag("--retries", "Number of retries", scalar.Int(scalar.Min(0))),
+				warg.NewCmdFlag("--prefix", "Prefix for copied file names", scalar.String(scalar.Default(""))),
+				warg.NewCmdFlag("--label", "Labels to add", dict.String(dict.Default(map[string]string{"team": "infra"}))),
+				warg.NewCmdFlag("--verbose", "Print each file", scalar.Bool(), warg.Switch(), warg.Negatable(), warg.Alias("-v")),
+				warg.NewCmdFlag("--ignore", "Patterns to skip", slice.String(), warg.RenamedFrom("--exclude")),
+				warg.NewCmdFlag("--secret", "Hidden flag", scalar.String(), warg.FlagHidden()),
+				warg.MutuallyExclusive("--mode", "--retries"),
+				warg.AllowForwardedArgs(),
+			),
+			warg.NewSubSection(
+				"k8s",
+				"Manage Kubernetes",
+				warg.SectionAliases("kube"),
+				warg.NewSectionFlag("--cluster", "Cluster to use", scalar.String(), warg.Required()),
+				warg.NewSubCmd("get", "Get resources", warg.Unimplemented(), warg.CmdDeprecated("use kubectl", warg.RemovedIn("v2.0.0"))),
+				warg.NewSubCmd("internal", "Hidden command", warg.Unimplemented(), warg.CmdHidden()),
+			),
+		),
+		warg.EnvPrefix("MYAPP"),
+		warg.SkipAll(),
+	)
+}
+
+func TestJSONHelp(t *testing.T) {
+	updateGolden := os.Getenv("WARG_TEST_UPDATE_GOLDEN") != ""
+	app := describeTestApp()
+	warg.GoldenTest(
+		t,
+		warg.GoldenTestArgs{
+			App:             &app,
+			UpdateGolden:    updateGolden,
+			ExpectActionErr: false,
+			Args:            []string{"k8s", "get", "--help", "json"},
+		},
+		warg.ParseWithLookupEnv(warg.LookupMap(nil)),
+	)
+}
+
+func TestApp_Describe(t *testing.T) {
+	app := describeTestApp()
+	desc := app.Describe()
+	require.Equal(t, warg.DescriptionSchemaVersion, desc.SchemaVersion)
+
+	b, err := json.Marshal(desc)
+	require.Nil(t, err)
+
+	// the JSON round-trips, so tools can read a description and write it back unchanged
+	var actual warg.AppDescription
+	require.Nil(t, json.Unmarshal(b, &actual))
+	b2, err := json.Marshal(actual)
+	require.Nil(t, err)
+	require.JSONEq(t, string(b), string(b2))
+
+	require.Equal(t, "myapp", actual.Name)
+	require.Len(t, actual.RootSection.Cmds, 1)
+	copyCmd := actual.RootSection.Cmds[0]
+	require.Equal(t, []string{"cp"}, copyCmd.Aliases)
+	require.True(t, copyCmd.AllowForwardedArgs)
+
+	flagNames := make([]string, 0, len(copyCmd.Flags))
+	for _, fl := range copyCmd.Flags {
+		flagNames = append(flagNames, fl.Name)
+	}
+	require.Equal(t, []string{"--ignore", "--label", "--mode", "--prefix", "--retries", "--verbose"}, flagNames)
+
+	// an empty default is still a default
+	prefix := copyCmd.Flags[3]
+	require.True(t, prefix.HasDefault)
+	require.Equal(t, "", prefix.Default)
+	require.False(t, copyCmd.Flags[4].HasDefault)
+
+	// slice and dict defaults keep their types after a round-trip
+	require.Equal(t, []string{"out.txt"}, copyCmd.Positionals[1].DefaultSlice)
+	require.Equal(t, map[string]string{"team": "infra"}, copyCmd.Flags[1].DefaultDict)
+
+	require.Len(t, actual.RootSection.Sections, 1)
+	k8s := actual.RootSection.Sections[0]
+	require.Equal(t, "k8s", k8s.Name)
+	require.Equal(t, "--cluster", k8s.Flags[0].Name)
+	require.Equal(t, []string{"MYAPP_K8S_CLUSTER", "MYAPP_CLUSTER"}, k8s.Flags[0].EnvVars)
+	require.Len(t, k8s.Cmds, 1)
+	require.Equal(t, "v2.0.0", k8s.Cmds[0].Deprecation.RemovedIn)
+}
@@ -0,0 +1,576 @@
+package warg
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.bbkane.com/warg/colerr"
+	"go.bbkane.com/warg/value/scalar"
+)
+
+// DocFile is a documentation file generated by [App.MarkdownDocs] or [App.HTMLDocs].
+type DocFile struct {
+	// FileName is the file's name, such as "myapp-db-migrate.md"
+	FileName string
+	// Content is the file's contents
+	Content string
+}
+
+// DocsCmd adds an opt-in "docs" command that writes the app's documentation to the directory
+// passed with --output-dir, as Markdown (see [App.WriteMarkdownDocs]) or HTML (see [App.WriteHTMLDocs]).
+func DocsCmd() AppOpt {
+	return func(a *App) {
+		a.DocsCmd = true
+	}
+}
+
+func docsCmd() SectionOpt {
+	return NewSubCmd(
+		"docs",
+		"Write documentation for this app",
+		docsCmdAction,
+		CmdSkipInheritedMiddlewares(),
+		CmdHelpLong("Write documentation for every section and command to the output directory: a Markdown file per section and command with an index.md, or a single index.html page. The output is deterministic, so it can be committed and diffed in CI."),
+		NewCmdFlag(
+			"--output-dir",
+			"Directory to write documentation to",
+			scalar.String(
+				scalar.Default("docs"),
+			),
+			Alias("-o"),
+		),
+		NewCmdFlag(
+			"--format",
+			"Documentation format",
+			scalar.String(
+				scalar.Choices("markdown", "html"),
+				scalar.Default("markdown"),
+			),
+		),
+	)
+}
+
+func docsCmdAction(cmdCtx CmdContext) error {
+	dir := cmdCtx.Flags["--output-dir"].(string)
+	format := cmdCtx.Flags["--format"].(string)
+	var err error
+	if format == "html" {
+		err = cmdCtx.App.WriteHTMLDocs(dir)
+	} else {
+		err = cmdCtx.App.WriteMarkdownDocs(dir)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmdCtx.Stdout, "Wrote %s docs to %s\n", format, dir)
+	return nil
+}
+
+// WriteMarkdownDocs writes the files from [App.MarkdownDocs] to dir, creating it if needed.
+func (app *App) WriteMarkdownDocs(dir string) error {
+	return writeDocFiles(dir, app.MarkdownDocs())
+}
+
+// WriteHTMLDocs writes the file from [App.HTMLDocs] to dir, creating it if needed.
+func (app *App) WriteHTMLDocs(dir string) error {
+	return writeDocFiles(dir, app.HTMLDocs())
+}
+
+func writeDocFiles(dir string, files []DocFile) error {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return colerr.NewWrappedf(err, "Could not create docs directory: %s", dir)
+	}
+	for _, file := range files {
+		filePath := filepath.Join(dir, file.FileName)
+		err := os.WriteFile(filePath, []byte(file.Content), 0644)
+		if err != nil {
+			return colerr.NewWrappedf(err, "Could not write docs file: %s", filePath)
+		}
+	}
+	return nil
+}
+
+// docDetail is a labeled fact about a flag or positional, such as "default: 8080".
+type docDetail struct {
+	Label string
+	Value string
+}
+
+// docFlag is a flag or positional as shown in docs.
+type docFlag struct {
+	// Names are the flag name then its aliases, or the positional name
+	Names     []string
+	Type      string
+	HelpShort string
+	Details   []docDetail
+}
+
+// docFlagGroup is a list of flags under a heading. Heading is "" for flags without a [FlagGroup].
+type docFlagGroup struct {
+	Heading string
+	Flags   []docFlag
+}
+
+// docFlagSection is a titled list of flag groups, such as a command's own flags or the flags it inherits from a section.
+type docFlagSection struct {
+	Title string
+	// LinkID is the page declaring the flags, or "" if it's the current page
+	LinkID string
+	// LinkText is the title of the page declaring the flags, or "" if it's the current page
+	LinkText string
+	Groups   []docFlagGroup
+}
+
+// docLink links to a child section or command page.
+type docLink struct {
+	Name      string
+	ID        string
+	HelpShort string
+}
+
+// docPage holds what the docs show for one section or command. It's built from [App.Describe],
+// like man pages, so each output format renders the same data as "--help json".
+type docPage struct {
+	// ID is the page's file name without extension and its HTML anchor, such as "myapp-db-migrate"
+	ID string
+	// Path is the section path plus command name, not including the app name
+	Path        []string
+	IsCmd       bool
+	HelpShort   string
+	HelpLong    string
+	Footer      string
+	Deprecation string
+	// Usage is only set for commands
+	Usage        string
+	Sections     []docLink
+	Cmds         []docLink
+	Positionals  []docFlag
+	FlagSections []docFlagSection
+	Constraints  []string
+}
+
+// Title returns the app name and path, such as "myapp db migrate".
+func (p docPage) Title(appName string) string {
+	return strings.Join(append([]string{appName}, p.Path...), " ")
+}
+
+// docPageID returns the page ID for a path. The root section's page is the index.
+func (app *App) docPageID(path []string) string {
+	if len(path) == 0 {
+		return "index"
+	}
+	return app.manPageName(path)
+}
+
+// docPages gathers a page for every section and command in [App.Describe], in help order (see depthFirstSections).
+// The root section's page also lists the global flags.
+func (app *App) docPages() []docPage {
+	desc := app.Describe()
+	var pages []docPage
+	for _, flatSec := range depthFirstSections(app.RootSection, nil) {
+		ds := desc.section(flatSec.Path)
+		pages = append(pages, app.sectionDocPage(desc, ds))
+		for _, cmd := range ds.Sec().Cmds {
+			pages = append(pages, app.cmdDocPage(ds, cmd))
+		}
+	}
+	return pages
+}
+
+func (app *App) sectionDocPage(desc AppDescription, ds describedSection) docPage {
+	sec := ds.Sec()
+	page := docPage{
+		ID:           app.docPageID(ds.Path),
+		Path:         ds.Path,
+		IsCmd:        false,
+		HelpShort:    sec.HelpShort,
+		HelpLong:     sec.HelpLong,
+		Footer:       sec.Footer,
+		Deprecation:  "",
+		Usage:        "",
+		Sections:     nil,
+		Cmds:         nil,
+		Positionals:  nil,
+		FlagSections: nil,
+		Constraints:  nil,
+	}
+	if sec.Deprecation != nil {
+		page.Deprecation = sec.Deprecation.Message
+	}
+	for _, child := range sec.Sections {
+		page.Sections = append(page.Sections, docLink{
+			Name:      withAliases(child.Name, child.Aliases),
+			ID:        app.docPageID(ds.ChildPath(child.Name)),
+			HelpShort: child.HelpShort + child.Deprecation.helpSuffix(),
+		})
+	}
+	for _, child := range sec.Cmds {
+		page.Cmds = append(page.Cmds, docLink{
+			Name:      withAliases(child.Name, child.Aliases),
+			ID:        app.docPageID(ds.ChildPath(child.Name)),
+			HelpShort: child.HelpShort + child.Deprecation.helpSuffix(),
+		})
+	}
+	if groups := docFlagGroups(sec.Flags); len(groups) > 0 {
+		page.FlagSections = append(page.FlagSections, docFlagSection{
+			Title:    "Section Flags",
+			LinkID:   "",
+			LinkText: "",
+			Groups:   groups,
+		})
+	}
+	if len(ds.Path) == 0 {
+		if groups := docFlagGroups(desc.GlobalFlags); len(groups) > 0 {
+			page.FlagSections = append(page.FlagSections, docFlagSection{
+				Title:    "Global Flags",
+				LinkID:   "",
+				LinkText: "",
+				Groups:   groups,
+			})
+		}
+	}
+	return page
+}
+
+func (app *App) cmdDocPage(ds describedSection, cmd CmdDescription) docPage {
+	cmdPath := ds.ChildPath(cmd.Name)
+	usage := strings.Join(append([]string{app.Name}, cmdPath...), " ") + " [flags]"
+	if len(cmd.Positionals) > 0 {
+		usage += " " + positionalsUsage(cmd.Positionals)
+	}
+	if cmd.AllowForwardedArgs {
+		usage += " -- [args]"
+	}
+	page := docPage{
+		ID:           app.docPageID(cmdPath),
+		Path:         cmdPath,
+		IsCmd:        true,
+		HelpShort:    cmd.HelpShort,
+		HelpLong:     cmd.HelpLong,
+		Footer:       cmd.Footer,
+		Deprecation:  "",
+		Usage:        usage,
+		Sections:     nil,
+		Cmds:         nil,
+		Positionals:  nil,
+		FlagSections: nil,
+		Constraints:  cmd.Constraints,
+	}
+	if cmd.Deprecation != nil {
+		page.Deprecation = cmd.Deprecation.Message
+	}
+	for _, pos := range cmd.Positionals {
+		var details []docDetail
+		if pos.Required {
+			details = append(details, docDetail{Label: "required", Value: "true"})
+		}
+		details = append(details, docValueDetails(pos.ValueDescription)...)
+		page.Positionals = append(page.Positionals, docFlag{
+			Names:     []string{pos.Name},
+			Type:      pos.Type,
+			HelpShort: pos.HelpShort,
+			Details:   details,
+		})
+	}
+	if groups := docFlagGroups(cmd.Flags); len(groups) > 0 {
+		page.FlagSections = append(page.FlagSections, docFlagSection{
+			Title:    "Flags",
+			LinkID:   "",
+			LinkText: "",
+			Groups:   groups,
+		})
+	}
+	for _, inherited := range ds.InheritedFlags() {
+		page.FlagSections = append(page.FlagSections, docFlagSection{
+			Title:    app.inheritedFlagsHeader(inherited.Path),
+			LinkID:   app.docPageID(inherited.Path),
+			LinkText: strings.Join(append([]string{app.Name}, inherited.Path...), " "),
+			Groups:   docFlagGroups(inherited.Flags),
+		})
+	}
+	return page
+}
+
+// docFlagGroups returns flags grouped by [FlagGroup].
+func docFlagGroups(flags []FlagDescription) []docFlagGroup {
+	var groups []docFlagGroup
+	for _, group := range groupFlags(flags) {
+		dg := docFlagGroup{Heading: group.Name, Flags: nil}
+		for _, fd := range group.Flags {
+			typ := fd.Type
+			// switches don't take a value
+			if fd.Switch {
+				typ = ""
+			}
+			var details []docDetail
+			if fd.Required {
+				details = append(details, docDetail{Label: "required", Value: "true"})
+			}
+			if fd.Deprecation != nil {
+				details = append(details, docDetail{Label: "deprecated", Value: fd.Deprecation.Message})
+			}
+			details = append(details, docValueDetails(fd.ValueDescription)...)
+			if len(fd.EnvVars) > 0 {
+				details = append(details, docDetail{Label: "envvars", Value: strings.Join(fd.EnvVars, ", ")})
+			}
+			if fd.ConfigPath != "" {
+				details = append(details, docDetail{Label: "configpath", Value: fd.ConfigPath})
+			}
+			if fd.Negatable {
+				details = append(details, docDetail{Label: "negation", Value: negatedName(fd.Name)})
+			}
+			dg.Flags = append(dg.Flags, docFlag{
+				Names:     append([]string{fd.Name}, fd.Aliases...),
+				Type:      typ,
+				HelpShort: fd.HelpShort,
+				Details:   details,
+			})
+		}
+		groups = append(groups, dg)
+	}
+	return groups
+}
+
+// docValueDetails returns the value's choices, constraints, and default, if it has them.
+func docValueDetails(vd ValueDescription) []docDetail {
+	var details []docDetail
+	if len(vd.Choices) > 0 {
+		details = append(details, docDetail{Label: "choices", Value: strings.Join(vd.Choices, ", ")})
+	}
+	if len(vd.Constraints) > 0 {
+		details = append(details, docDetail{Label: "constraints", Value: strings.Join(vd.Constraints, ", ")})
+	}
+	if def, ok := vd.defaultString(); ok {
+		details = append(details, docDetail{Label: "default", Value: def})
+	}
+	return details
+}
+
+// MarkdownDocs renders a Markdown file for every visible section and command, in help order.
+// The root section's file is index.md, which also lists every command and the global flags.
+// Other files are named like man pages ("myapp-db-migrate.md") and link to each other.
+func (app *App) MarkdownDocs() []DocFile {
+	pages := app.docPages()
+	files := make([]DocFile, 0, len(pages))
+	for _, page := range pages {
+		var b strings.Builder
+		app.markdownWritePage(&b, page, pages)
+		files = append(files, DocFile{FileName: page.ID + ".md", Content: b.String()})
+	}
+	return files
+}
+
+// markdownWritePage writes one page. allPages is used to list every command on the index.
+func (app *App) markdownWritePage(b *strings.Builder, page docPage, allPages []docPage) {
+	fmt.Fprintf(b, "# %s\n\n", page.Title(app.Name))
+
+	// breadcrumbs link to each parent section
+	if len(page.Path) > 0 {
+		crumbs := []string{fmt.Sprintf("[%s](%s.md)", app.Name, app.docPageID(nil))}
+		for i := range page.Path[:len(page.Path)-1] {
+			crumbs = append(crumbs, fmt.Sprintf("[%s](%s.md)", page.Path[i], app.docPageID(page.Path[:i+1])))
+		}
+		crumbs = append(crumbs, page.Path[len(page.Path)-1])
+		fmt.Fprintf(b, "%s\n\n", strings.Join(crumbs, " > "))
+	}
+
+	fmt.Fprintf(b, "%s\n\n", helpLongOrShort(page.HelpLong, page.HelpShort))
+	if page.Deprecation != "" {
+		fmt.Fprintf(b, "> **Deprecated:** %s\n\n", page.Deprecation)
+	}
+	if page.Usage != "" {
+		fmt.Fprintf(b, "## Usage\n\n```\n%s\n```\n\n", page.Usage)
+	}
+
+	markdownWriteLinks := func(title string, links []docLink) {
+		if len(links) == 0 {
+			return
+		}
+		fmt.Fprintf(b, "## %s\n\n| Name | Description |\n| --- | --- |\n", title)
+		for _, link := range links {
+			fmt.Fprintf(b, "| [%s](%s.md) | %s |\n", markdownCell(link.Name), link.ID, markdownCell(link.HelpShort))
+		}
+		b.WriteString("\n")
+	}
+	markdownWriteLinks("Sections", page.Sections)
+	markdownWriteLinks("Commands", page.Cmds)
+
+	if len(page.Path) == 0 {
+		var allCmds []docLink
+		for _, p := range allPages {
+			if p.IsCmd {
+				allCmds = append(allCmds, docLink{Name: p.Title(app.Name), ID: p.ID, HelpShort: p.HelpShort})
+			}
+		}
+		markdownWriteLinks("All Commands", allCmds)
+	}
+
+	if len(page.Positionals) > 0 {
+		b.WriteString("## Positional Arguments\n\n")
+		markdownWriteFlagTable(b, page.Positionals)
+	}
+
+	for _, fs := range page.FlagSections {
+		fmt.Fprintf(b, "## %s\n\n", fs.Title)
+		if fs.LinkID != "" {
+			fmt.Fprintf(b, "Declared by [%s](%s.md).\n\n", fs.LinkText, fs.LinkID)
+		}
+		for _, group := range fs.Groups {
+			if group.Heading != "" {
+				fmt.Fprintf(b, "### %s\n\n", group.Heading)
+			}
+			markdownWriteFlagTable(b, group.Flags)
+		}
+	}
+
+	if len(page.Constraints) > 0 {
+		b.WriteString("## Flag Constraints\n\n")
+		for _, c := range page.Constraints {
+			fmt.Fprintf(b, "- %s\n", c)
+		}
+		b.WriteString("\n")
+	}
+
+	if page.Footer != "" {
+		fmt.Fprintf(b, "## Footer\n\n```\n%s\n```\n\n", strings.Trim(page.Footer, "\n"))
+	}
+
+	if page.IsCmd && len(app.GlobalFlags.visibleSortedNames()) > 0 {
+		fmt.Fprintf(b, "See [%s](%s.md#global-flags) for global flags.\n", app.Name, app.docPageID(nil))
+	}
+}
+
+// markdownWriteFlagTable writes flags or positionals as a table.
+func markdownWriteFlagTable(b *strings.Builder, flags []docFlag) {
+	b.WriteString("| Name | Type | Description | Details |\n| --- | --- | --- | --- |\n")
+	for _, fl := range flags {
+		names := make([]string, 0, len(fl.Names))
+		for _, name := range fl.Names {
+			names = append(names, "`"+name+"`")
+		}
+		details := make([]string, 0, len(fl.Details))
+		for _, d := range fl.Details {
+			details = append(details, d.Label+": "+d.Value)
+		}
+		fmt.Fprintf(
+			b,
+			"| %s | %s | %s | %s |\n",
+			strings.Join(names, ", "),
+			markdownCell(fl.Type),
+			markdownCell(fl.HelpShort),
+			markdownCell(strings.Join(details, "<br>")),
+		)
+	}
+	b.WriteString("\n")
+}
+
+// markdownCell escapes s for use in a table cell.
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// HTMLDocs renders a single index.html page documenting every visible section and command, in help order,
+// with a table of contents linking to each.
+func (app *App) HTMLDocs() []DocFile {
+	pages := app.docPages()
+	var b strings.Builder
+	esc := html.EscapeString
+
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&b, "<title>%s</title>\n", esc(app.Name))
+	b.WriteString("</head>\n<body>\n")
+
+	b.WriteString("<nav>\n<ul>\n")
+	for _, page := range pages {
+		fmt.Fprintf(&b, "<li><a href=\"#%s\">%s</a></li>\n", page.ID, esc(page.Title(app.Name)))
+	}
+	b.WriteString("</ul>\n</nav>\n")
+
+	for _, page := range pages {
+		fmt.Fprintf(&b, "<section id=\"%s\">\n", page.ID)
+		fmt.Fprintf(&b, "<h1>%s</h1>\n", esc(page.Title(app.Name)))
+		for _, para := range strings.Split(helpLongOrShort(page.HelpLong, page.HelpShort), "\n\n") {
+			fmt.Fprintf(&b, "<p>%s</p>\n", esc(strings.TrimSpace(para)))
+		}
+		if page.Deprecation != "" {
+			fmt.Fprintf(&b, "<p><strong>Deprecated:</strong> %s</p>\n", esc(page.Deprecation))
+		}
+		if page.Usage != "" {
+			fmt.Fprintf(&b, "<h2>Usage</h2>\n<pre>%s</pre>\n", esc(page.Usage))
+		}
+
+		htmlWriteLinks := func(title string, links []docLink) {
+			if len(links) == 0 {
+				return
+			}
+			fmt.Fprintf(&b, "<h2>%s</h2>\n<table>\n<tr><th>Name</th><th>Description</th></tr>\n", esc(title))
+			for _, link := range links {
+				fmt.Fprintf(&b, "<tr><td><a href=\"#%s\">%s</a></td><td>%s</td></tr>\n", link.ID, esc(link.Name), esc(link.HelpShort))
+			}
+			b.WriteString("</table>\n")
+		}
+		htmlWriteLinks("Sections", page.Sections)
+		htmlWriteLinks("Commands", page.Cmds)
+
+		if len(page.Positionals) > 0 {
+			b.WriteString("<h2>Positional Arguments</h2>\n")
+			htmlWriteFlagTable(&b, page.Positionals)
+		}
+		for _, fs := range page.FlagSections {
+			if fs.LinkID != "" {
+				fmt.Fprintf(&b, "<h2><a href=\"#%s\">%s</a></h2>\n", fs.LinkID, esc(fs.Title))
+			} else {
+				fmt.Fprintf(&b, "<h2>%s</h2>\n", esc(fs.Title))
+			}
+			for _, group := range fs.Groups {
+				if group.Heading != "" {
+					fmt.Fprintf(&b, "<h3>%s</h3>\n", esc(group.Heading))
+				}
+				htmlWriteFlagTable(&b, group.Flags)
+			}
+		}
+		if len(page.Constraints) > 0 {
+			b.WriteString("<h2>Flag Constraints</h2>\n<ul>\n")
+			for _, c := range page.Constraints {
+				fmt.Fprintf(&b, "<li>%s</li>\n", esc(c))
+			}
+			b.WriteString("</ul>\n")
+		}
+		if page.Footer != "" {
+			fmt.Fprintf(&b, "<h2>Footer</h2>\n<pre>%s</pre>\n", esc(strings.Trim(page.Footer, "\n")))
+		}
+		b.WriteString("</section>\n")
+	}
+
+	b.WriteString("</body>\n</html>\n")
+	return []DocFile{{FileName: "index.html", Content: b.String()}}
+}
+
+// htmlWriteFlagTable writes flags or positionals as a table.
+func htmlWriteFlagTable(b *strings.Builder, flags []docFlag) {
+	b.WriteString("<table>\n<tr><th>Name</th><th>Type</th><th>Description</th><th>Details</th></tr>\n")
+	for _, fl := range flags {
+		names := make([]string, 0, len(fl.Names))
+		for _, name := range fl.Names {
+			names = append(names, "<code>"+html.EscapeString(name)+"</code>")
+		}
+		details := make([]string, 0, len(fl.Details))
+		for _, d := range fl.Details {
+			details = append(details, html.EscapeString(d.Label+": "+d.Value))
+		}
+		fmt.Fprintf(
+			b,
+			"<tr><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>\n",
+			strings.Join(names, ", "),
+			html.EscapeString(fl.Type),
+			html.EscapeString(fl.HelpShort),
+			strings.Join(details, "<br>"),
+		)
+	}
+	b.WriteString("</table>\n")
+}
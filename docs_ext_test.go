@@ -0,0 +1,98 @@
+package warg_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.bbkane.com/warg"
+)
+
+func TestApp_MarkdownDocs(t *testing.T) {
+	updateGolden := os.Getenv("WARG_TEST_UPDATE_GOLDEN") != ""
+	app := manTestApp()
+
+	files := app.MarkdownDocs()
+
+	fileNames := make([]string, 0, len(files))
+	for _, file := range files {
+		fileNames = append(fileNames, file.FileName)
+	}
+	require.Equal(t, []string{"index.md", "myapp-copy.md", "myapp-k8s.md", "myapp-k8s-get.md"}, fileNames)
+
+	goldenDir := filepath.Join("testdata", t.Name())
+	for _, file := range files {
+		goldenPath := filepath.Join(goldenDir, file.FileName)
+		if updateGolden {
+			require.Nil(t, os.MkdirAll(goldenDir, 0700))
+			require.Nil(t, os.WriteFile(goldenPath, []byte(file.Content), 0600))
+		}
+		expected, err := os.ReadFile(goldenPath)
+		require.Nil(t, err)
+		require.Equal(t, string(expected), file.Content, "doc file %s differs from golden file", file.FileName)
+	}
+}
+
+func TestApp_HTMLDocs(t *testing.T) {
+	updateGolden := os.Getenv("WARG_TEST_UPDATE_GOLDEN") != ""
+	app := manTestApp()
+
+	files := app.HTMLDocs()
+	require.Len(t, files, 1)
+	require.Equal(t, "index.html", files[0].FileName)
+
+	goldenPath := filepath.Join("testdata", t.Name(), "index.html")
+	if updateGolden {
+		require.Nil(t, os.MkdirAll(filepath.Dir(goldenPath), 0700))
+		require.Nil(t, os.WriteFile(goldenPath, []byte(files[0].Content), 0600))
+	}
+	expected, err := os.ReadFile(goldenPath)
+	require.Nil(t, err)
+	require.Equal(t, string(expected), files[0].Content)
+}
+
+func TestDocsCmd(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		expected func(app *warg.App) []warg.DocFile
+	}{
+		{
+			name:     "markdown",
+			format:   "markdown",
+			expected: (*warg.App).MarkdownDocs,
+		},
+		{
+			name:     "html",
+			format:   "html",
+			expected: (*warg.App).HTMLDocs,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := manTestApp(warg.DocsCmd())
+			require.Nil(t, app.Validate())
+
+			dir := filepath.Join(t.TempDir(), "docs")
+			stdout, err := os.CreateTemp(t.TempDir(), "stdout")
+			require.Nil(t, err)
+			defer stdout.Close()
+
+			pr, err := app.Parse(
+				[]string{"docs", "--output-dir", dir, "--format", tt.format},
+				warg.ParseWithLookupEnv(warg.LookupMap(nil)),
+				warg.ParseWithStdout(stdout),
+			)
+			require.Nil(t, err)
+			require.Nil(t, pr.Action(pr.Context))
+
+			for _, file := range tt.expected(&app) {
+				actual, err := os.ReadFile(filepath.Join(dir, file.FileName))
+				require.Nil(t, err)
+				require.Equal(t, file.Content, string(actual))
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+// Package dotenv parses .env files of KEY=VALUE lines.
+//
+// Supported syntax:
+//
+//   - blank lines and lines starting with # are ignored
+//   - an optional "export " prefix
+//   - unquoted values, with trailing " # comments" removed
+//   - single-quoted values, taken literally
+//   - double-quoted values, with \n, \r, \t, \", \\, and \$ escapes. They can span multiple lines.
+//   - ${VAR} interpolation in unquoted and double-quoted values
+package dotenv
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"go.bbkane.com/warg/colerr"
+)
+
+// LookupEnv looks up variables referenced by ${VAR}. Satisfiable by [os.LookupEnv].
+type LookupEnv func(key string) (string, bool)
+
+// ReadFile reads and parses the dotenv file at filePath. See [Parse].
+func ReadFile(filePath string, lookupEnv LookupEnv, earlier map[string]string) (map[string]string, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(string(content), lookupEnv, earlier)
+}
+
+// Parse parses dotenv content into a map. ${VAR} is replaced by the first of:
+//
+//   - the value of VAR from lookupEnv (the environment takes precedence over dotenv files)
+//   - the value of VAR defined earlier in the content
+//   - the value of VAR in earlier, which holds variables from dotenv files loaded before this one
+//   - the empty string
+//
+// This is the order a program that layers the environment over dotenv files (later files overriding
+// earlier ones) resolves VAR in, so ${VAR} expands to the value the program would see at that point.
+// lookupEnv and earlier may be nil. Variables in earlier aren't included in the result.
+func Parse(content string, lookupEnv LookupEnv, earlier map[string]string) (map[string]string, error) {
+	vars := make(map[string]string)
+	lookup := func(key string) string {
+		if lookupEnv != nil {
+			if val, exists := lookupEnv(key); exists {
+				return val
+			}
+		}
+		if val, exists := vars[key]; exists {
+			return val
+		}
+		return earlier[key]
+	}
+
+	lines := strings.Split(content, "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNum := strconv.Itoa(i + 1)
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+
+		key, rawVal, found := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !found {
+			return nil, colerr.NewWrappedf(nil, "line %s: expected KEY=VALUE", lineNum)
+		}
+		if !isValidKey(key) {
+			return nil, colerr.NewWrappedf(nil, "line %s: invalid key: %s", lineNum, key)
+		}
+
+		rawVal = strings.TrimSpace(rawVal)
+		// double-quoted values continue until the closing quote
+		for strings.HasPrefix(rawVal, "\"") && !hasClosingDoubleQuote(rawVal) && i+1 < len(lines) {
+			i++
+			rawVal += "\n" + strings.TrimSuffix(lines[i], "\r")
+		}
+
+		val, err := parseValue(rawVal, lookup)
+		if err != nil {
+			return nil, colerr.NewWrappedf(err, "line %s: invalid value for key: %s", lineNum, key)
+		}
+		vars[key] = val
+	}
+	return vars, nil
+}
+
+func isValidKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, r := range key {
+		isLetter := (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z') || r == '_'
+		isDigit := r >= '0' && r <= '9'
+		if !isLetter && !(i > 0 && (isDigit || r == '.')) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasClosingDoubleQuote reports whether raw (which starts with a double quote) contains an unescaped closing double quote.
+func hasClosingDoubleQuote(raw string) bool {
+	for i := 1; i < len(raw); i++ {
+		switch raw[i] {
+		case '\\':
+			i++
+		case '"':
+			return true
+		}
+	}
+	return false
+}
+
+// checkTrailing returns an error if anything other than whitespace or a comment follows a closing quote.
+func checkTrailing(rest string) error {
+	rest = strings.TrimSpace(rest)
+	if rest != "" && !strings.HasPrefix(rest, "#") {
+		return colerr.NewWrappedf(nil, "unexpected characters after closing quote: %s", rest)
+	}
+	return nil
+}
+
+func parseValue(raw string, lookup func(string) string) (string, error) {
+	switch {
+	case strings.HasPrefix(raw, "'"):
+		end := strings.Index(raw[1:], "'")
+		if end == -1 {
+			return "", colerr.NewWrapped(nil, "unclosed single quote")
+		}
+		return raw[1 : end+1], checkTrailing(raw[end+2:])
+
+	case strings.HasPrefix(raw, "\""):
+		var b strings.Builder
+		for i := 1; i < len(raw); i++ {
+			switch c := raw[i]; c {
+			case '"':
+				return b.String(), checkTrailing(raw[i+1:])
+			case '\\':
+				if i+1 >= len(raw) {
+					return "", colerr.NewWrapped(nil, "unclosed double quote")
+				}
+				i++
+				switch raw[i] {
+				case 'n':
+					b.WriteByte('\n')
+				case 'r':
+					b.WriteByte('\r')
+				case 't':
+					b.WriteByte('\t')
+				default:
+					// \", \\, \$, and unknown escapes are taken literally
+					b.WriteByte(raw[i])
+				}
+			case '$':
+				consumed, err := interpolate(&b, raw[i:], lookup)
+				if err != nil {
+					return "", err
+				}
+				i += consumed - 1
+			default:
+				b.WriteByte(c)
+			}
+		}
+		return "", colerr.NewWrapped(nil, "unclosed double quote")
+
+	default:
+		// remove trailing comments
+		if idx := strings.Index(raw, " #"); idx != -1 {
+			raw = strings.TrimSpace(raw[:idx])
+		}
+		var b strings.Builder
+		for i := 0; i < len(raw); i++ {
+			if raw[i] == '$' {
+				consumed, err := interpolate(&b, raw[i:], lookup)
+				if err != nil {
+					return "", err
+				}
+				i += consumed - 1
+				continue
+			}
+			b.WriteByte(raw[i])
+		}
+		return b.String(), nil
+	}
+}
+
+// interpolate writes the expansion of a ${VAR} at the start of s (which starts with "$") to b,
+// returning how many bytes of s were consumed. A "$" not followed by "{" is written literally.
+func interpolate(b *strings.Builder, s string, lookup func(string) string) (int, error) {
+	if !strings.HasPrefix(s, "${") {
+		b.WriteByte('$')
+		return 1, nil
+	}
+	end := strings.Index(s, "}")
+	if end == -1 {
+		return 0, colerr.NewWrapped(nil, "unclosed ${")
+	}
+	b.WriteString(lookup(s[2:end]))
+	return end + 1, nil
+}
@@ -0,0 +1,128 @@
+package dotenv_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.bbkane.com/warg/dotenv"
+)
+
+func TestParse(t *testing.T) {
+	lookupEnv := func(key string) (string, bool) {
+		if key == "HOME" {
+			return "/home/user", true
+		}
+		return "", false
+	}
+
+	tests := []struct {
+		name        string
+		content     string
+		expected    map[string]string
+		expectedErr bool
+	}{
+		{
+			name:        "unquoted",
+			content:     "KEY=value\n  SPACED = spaced value  \n",
+			expected:    map[string]string{"KEY": "value", "SPACED": "spaced value"},
+			expectedErr: false,
+		},
+		{
+			name:        "commentsAndBlankLines",
+			content:     "# comment\n\nKEY=value # trailing comment\nHASH=a#b\n",
+			expected:    map[string]string{"KEY": "value", "HASH": "a#b"},
+			expectedErr: false,
+		},
+		{
+			name:        "export",
+			content:     "export KEY=value\n",
+			expected:    map[string]string{"KEY": "value"},
+			expectedErr: false,
+		},
+		{
+			name:        "singleQuoted",
+			content:     "KEY='${HOME} \\n # not a comment' # comment\n",
+			expected:    map[string]string{"KEY": "${HOME} \\n # not a comment"},
+			expectedErr: false,
+		},
+		{
+			name:        "doubleQuoted",
+			content:     `KEY="line1\nline2 \"quoted\" \${HOME} # not a comment"` + "\n",
+			expected:    map[string]string{"KEY": "line1\nline2 \"quoted\" ${HOME} # not a comment"},
+			expectedErr: false,
+		},
+		{
+			name:        "interpolation",
+			content:     "DIR=${HOME}/app\nDATA=\"${DIR}/data\"\nMISSING=${NOPE}x\nDOLLAR=$5\n",
+			expected:    map[string]string{"DIR": "/home/user/app", "DATA": "/home/user/app/data", "MISSING": "x", "DOLLAR": "$5"},
+			expectedErr: false,
+		},
+		{
+			name:        "environmentWinsInterpolation",
+			content:     "HOME=/other\nDIR=${HOME}/app\n",
+			expected:    map[string]string{"HOME": "/other", "DIR": "/home/user/app"},
+			expectedErr: false,
+		},
+		{
+			name:        "earlierFilesInterpolation",
+			content:     "SHADOWED=file\nKEY=${EARLIER}-${SHADOWED}\n",
+			expected:    map[string]string{"SHADOWED": "file", "KEY": "earlier-file"},
+			expectedErr: false,
+		},
+		{
+			name:        "multiLineDoubleQuoted",
+			content:     "KEY=\"line1\n  line2 \\\" # not a comment\nline3\" # comment\r\nNEXT=value\n",
+			expected:    map[string]string{"KEY": "line1\n  line2 \" # not a comment\nline3", "NEXT": "value"},
+			expectedErr: false,
+		},
+		{
+			name:        "unclosedMultiLineQuote",
+			content:     "KEY=\"line1\nline2\nNEXT=value\n",
+			expected:    nil,
+			expectedErr: true,
+		},
+		{
+			name:        "unclosedQuote",
+			content:     "KEY=\"value\n",
+			expected:    nil,
+			expectedErr: true,
+		},
+		{
+			name:        "unclosedInterpolation",
+			content:     "KEY=${HOME\n",
+			expected:    nil,
+			expectedErr: true,
+		},
+		{
+			name:        "trailingAfterQuote",
+			content:     "KEY='value' extra\n",
+			expected:    nil,
+			expectedErr: true,
+		},
+		{
+			name:        "missingEquals",
+			content:     "KEY\n",
+			expected:    nil,
+			expectedErr: true,
+		},
+		{
+			name:        "invalidKey",
+			content:     "1KEY=value\n",
+			expected:    nil,
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := dotenv.Parse(tt.content, lookupEnv, map[string]string{"EARLIER": "earlier", "SHADOWED": "earlier"})
+			if tt.expectedErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.expected, actual)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package warg
 import (
 	"log"
 	"sort"
+	"strings"
 
 	"go.bbkane.com/warg/completion"
 	"go.bbkane.com/warg/value"
@@ -15,14 +16,21 @@ type FlagOpt func(*Flag)
 func NewFlag(helpShort string, empty value.EmptyConstructor, opts ...FlagOpt) Flag {
 	flag := Flag{
 		Alias:                 "",
+		Aliases:               nil,
 		Completions:           defaultFlagCompletions,
 		ConfigPath:            "",
+		Deprecation:           nil,
 		EmptyValueConstructor: empty,
 		EnvVars:               nil,
 		Group:                 "",
 		HelpShort:             helpShort,
+		Hidden:                false,
+		Negatable:             false,
+		RenamedFrom:           nil,
 		Required:              false,
+		Switch:                false,
 		UnsetSentinel:         nil,
+		Validators:            nil,
 	}
 	for _, opt := range opts {
 		opt(&flag)
@@ -104,6 +112,33 @@ func Required() FlagOpt {
 	}
 }
 
+// Switch makes a bool flag (scalar or slice) take no value: passing --verbose sets it to true.
+// An explicit value can still be passed with --verbose=false or --verbose=<UnsetSentinel>.
+func Switch() FlagOpt {
+	return func(f *Flag) {
+		f.Switch = true
+	}
+}
+
+// Negatable makes the flag a [Switch] and also accepts "--no-<name>" to set it to false.
+// The flag name must start with "--".
+//
+// Example:
+//
+//	app --verbose     // true
+//	app --no-verbose  // false
+func Negatable() FlagOpt {
+	return func(f *Flag) {
+		f.Switch = true
+		f.Negatable = true
+	}
+}
+
+// negatedName returns the "--no-<name>" form of a flag name.
+func negatedName(name string) string {
+	return "--no-" + strings.TrimPrefix(name, "--")
+}
+
 // UnsetSentinel defines a special value that, when passed on the command line, resets
 // the flag to its empty state, undoing any prior prior value from arguments / config / env vars / defaults
 // Conventionally set to "UNSET".
@@ -144,6 +179,17 @@ func (fm *FlagMap) SortedNames() []string {
 	return keys
 }
 
+// visibleSortedNames returns the names of flags that aren't hidden, in alphabetical order.
+func (fm FlagMap) visibleSortedNames() []string {
+	names := []string{}
+	for _, name := range fm.SortedNames() {
+		if !fm[name].Hidden {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
 // FlagNameGroup pairs a group name with sorted flag names for grouped help output.
 type FlagNameGroup struct {
 	// Name is the group name. Empty string means ungrouped (displayed first).
@@ -154,9 +200,13 @@ type FlagNameGroup struct {
 
 // groupedNames returns flag names organized by Group, with ungrouped flags first,
 // then groups in alphabetical order. Within each group, flags are sorted alphabetically.
+// Hidden flags are skipped, as this is only used for help output.
 func (fm *FlagMap) groupedNames() []FlagNameGroup {
 	groups := make(map[string][]string)
 	for name, flag := range *fm {
+		if flag.Hidden {
+			continue
+		}
 		groups[flag.Group] = append(groups[flag.Group], name)
 	}
 	for _, names := range groups {
@@ -203,6 +253,9 @@ type Flag struct {
 	// Alias is an alternative name for a flag, usually shorter :)
 	Alias string
 
+	// Aliases are more alternative names for a flag (see [Aliases])
+	Aliases []string
+
 	// Completions is a function that returns a list of completion candidates for this flag.
 	// Note that some flags in the cli.Context Flags map may not be set, even if they're required.
 	// TODO: get a comprehensive list of restrictions on the context.
@@ -211,6 +264,9 @@ type Flag struct {
 	// ConfigPath is the path from the config to the value the flag updates
 	ConfigPath string
 
+	// Deprecation marks this flag as deprecated (see [FlagDeprecated])
+	Deprecation *Deprecation
+
 	// EmptyConstructor tells flag how to make a value
 	EmptyValueConstructor value.EmptyConstructor
 
@@ -224,9 +280,24 @@ type Flag struct {
 	// HelpShort is a message for the user on how to use this flag
 	HelpShort string
 
+	// Hidden omits this flag from help and completions
+	Hidden bool
+
+	// Negatable means "--no-<name>" sets this (Switch) flag to false
+	Negatable bool
+
+	// RenamedFrom records previous names of this flag so they keep working (see [RenamedFrom])
+	RenamedFrom []FlagRename
+
 	// Required means the user MUST fill this flag
 	Required bool
 
+	// Switch means this bool flag is set to true when passed without a value
+	Switch bool
+
 	// When UnsetSentinal is passed as a flag value, Value is reset and SetBy is set to ""
 	UnsetSentinel *string
+
+	// Validators are run by App.Parse on the resolved value (see [FlagValidator])
+	Validators []FlagValidatorFunc
 }
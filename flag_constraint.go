@@ -0,0 +1,349 @@
+package warg
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.bbkane.com/warg/colerr"
+	"go.bbkane.com/warg/value"
+)
+
+// FlagConstraintType is the kind of relationship a [FlagConstraint] enforces between flags.
+type FlagConstraintType string
+
+const (
+	// FlagConstraintType_MutuallyExclusive allows at most one of the flags to be set.
+	FlagConstraintType_MutuallyExclusive FlagConstraintType = "mutually exclusive"
+	// FlagConstraintType_RequiredTogether requires that either all or none of the flags are set.
+	FlagConstraintType_RequiredTogether FlagConstraintType = "required together"
+	// FlagConstraintType_AtLeastOneOf requires that at least one of the flags is set.
+	FlagConstraintType_AtLeastOneOf FlagConstraintType = "at least one of"
+)
+
+// FlagConstraint is a declarative relationship between flags of a [Cmd] (or global flags),
+// checked by [App.Parse] after flag values are resolved. Flags only set by their default value
+// don't count as set.
+type FlagConstraint struct {
+	Type      FlagConstraintType
+	FlagNames []string
+}
+
+func newFlagConstraint(constraintType FlagConstraintType, flagNames []string) FlagConstraint {
+	return FlagConstraint{
+		Type:      constraintType,
+		FlagNames: flagNames,
+	}
+}
+
+// MutuallyExclusive allows at most one of the named flags to be set.
+//
+// Example usage:
+//
+//	warg.MutuallyExclusive("--json", "--table")
+func MutuallyExclusive(flagNames ...string) CmdOpt {
+	return func(cmd *Cmd) {
+		cmd.Constraints = append(cmd.Constraints, newFlagConstraint(FlagConstraintType_MutuallyExclusive, flagNames))
+	}
+}
+
+// RequiredTogether requires that if any of the named flags are set, all of them are.
+//
+// Example usage:
+//
+//	warg.RequiredTogether("--user", "--password")
+func RequiredTogether(flagNames ...string) CmdOpt {
+	return func(cmd *Cmd) {
+		cmd.Constraints = append(cmd.Constraints, newFlagConstraint(FlagConstraintType_RequiredTogether, flagNames))
+	}
+}
+
+// AtLeastOneOf requires that at least one of the named flags is set.
+//
+// Example usage:
+//
+//	warg.AtLeastOneOf("--id", "--name")
+func AtLeastOneOf(flagNames ...string) CmdOpt {
+	return func(cmd *Cmd) {
+		cmd.Constraints = append(cmd.Constraints, newFlagConstraint(FlagConstraintType_AtLeastOneOf, flagNames))
+	}
+}
+
+// String formats the constraint for help output, such as "mutually exclusive: --json, --table".
+func (fc FlagConstraint) String() string {
+	return string(fc.Type) + ": " + strings.Join(fc.FlagNames, ", ")
+}
+
+// flagSetByUser reports whether the flag was set by something other than its default.
+func flagSetByUser(flagValues ValueMap, flagName string) bool {
+	val, exists := flagValues[flagName]
+	if !exists {
+		return false
+	}
+	updatedBy := val.UpdatedBy()
+	return updatedBy != value.UpdatedByUnset && updatedBy != value.UpdatedByDefault
+}
+
+// describeFlagSource formats a flag name with where its value came from, such as "--json (passedflag: args[1])".
+func describeFlagSource(flagName string, flagSources map[string]FlagSource) string {
+	source, exists := flagSources[flagName]
+	if !exists {
+		return flagName
+	}
+	origin := source.Origin()
+	if origin == "" {
+		return flagName + " (" + string(source.UpdatedBy) + ")"
+	}
+	return flagName + " (" + string(source.UpdatedBy) + ": " + origin + ")"
+}
+
+// check returns an error if the resolved flag values violate the constraint.
+func (fc FlagConstraint) check(flagValues ValueMap, flagSources map[string]FlagSource) error {
+	var set []string
+	var missing []string
+	for _, name := range fc.FlagNames {
+		if flagSetByUser(flagValues, name) {
+			set = append(set, describeFlagSource(name, flagSources))
+		} else {
+			missing = append(missing, name)
+		}
+	}
+
+	switch fc.Type {
+	case FlagConstraintType_MutuallyExclusive:
+		if len(set) > 1 {
+			return colerr.NewWrappedf(nil, "Flags are mutually exclusive but got: %s", strings.Join(set, ", "))
+		}
+	case FlagConstraintType_RequiredTogether:
+		if len(set) > 0 && len(missing) > 0 {
+			return colerr.NewWrappedf(
+				nil,
+				"Flags are required together but got: %s; missing: %s",
+				strings.Join(set, ", "),
+				strings.Join(missing, ", "),
+			)
+		}
+	case FlagConstraintType_AtLeastOneOf:
+		if len(set) == 0 {
+			return colerr.NewWrappedf(nil, "At least one of these flags is required: %s", strings.Join(missing, ", "))
+		}
+	default:
+		return colerr.NewWrappedf(nil, "Unknown flag constraint type: %s", string(fc.Type))
+	}
+	return nil
+}
+
+// checkFlagConstraints checks all of the command's constraints and rules and joins any violations.
+func (cmd *Cmd) checkFlagConstraints(flagValues ValueMap, flagSources map[string]FlagSource) error {
+	var errs []error
+	for _, fc := range cmd.Constraints {
+		err := fc.check(flagValues, flagSources)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, fr := range cmd.Rules {
+		err := fr.check(flagValues, flagSources)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	err := errors.Join(errs...)
+	if err != nil {
+		return colerr.NewWrapped(err, "Flag constraints not satisfied")
+	}
+	return nil
+}
+
+// validateFlagConstraints checks that each constraint names at least two distinct flags that
+// exist on the command or globally, and that mutually exclusive flags aren't also required.
+func validateFlagConstraints(globalFlags FlagMap, comFlags FlagMap, constraints []FlagConstraint) error {
+	var errs []error
+	for _, fc := range constraints {
+		switch fc.Type {
+		case FlagConstraintType_MutuallyExclusive, FlagConstraintType_RequiredTogether, FlagConstraintType_AtLeastOneOf:
+		default:
+			errs = append(errs, colerr.NewWrappedf(nil, "Unknown flag constraint type: %s", string(fc.Type)))
+			continue
+		}
+		if len(fc.FlagNames) < 2 {
+			errs = append(errs, colerr.NewWrappedf(nil, "Flag constraints must name at least 2 flags: %s", fc.String()))
+		}
+		seen := make(map[string]bool)
+		requiredCount := 0
+		for _, name := range fc.FlagNames {
+			if seen[name] {
+				errs = append(errs, colerr.NewWrappedf(nil, "Flag constraint names a flag more than once: %s", fmt.Sprintf("%s: %s", fc.String(), name)))
+			}
+			seen[name] = true
+			fl, exists := comFlags[name]
+			if !exists {
+				fl, exists = globalFlags[name]
+			}
+			if !exists {
+				errs = append(errs, colerr.NewWrappedf(nil, "Flag constraint names an unknown flag: %s", fmt.Sprintf("%s: %s", fc.String(), name)))
+				continue
+			}
+			if fl.Required {
+				requiredCount++
+			}
+		}
+		if fc.Type == FlagConstraintType_MutuallyExclusive && requiredCount > 0 {
+			errs = append(errs, colerr.NewWrappedf(nil, "Mutually exclusive flags must not be required: %s", fc.String()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// FlagRuleType is the kind of conditional relationship a [FlagRule] enforces.
+type FlagRuleType string
+
+const (
+	// FlagRuleType_RequiredIf requires FlagName to be set when IfFlagName's value equals IfValue.
+	FlagRuleType_RequiredIf FlagRuleType = "required if"
+	// FlagRuleType_DependsOn only allows FlagName to be set when IfFlagName is also set. Defaults don't count as set.
+	FlagRuleType_DependsOn FlagRuleType = "depends on"
+)
+
+// FlagRule is a conditional relationship between two flags of a [Cmd] (or global flags),
+// checked by [App.Parse] after flag values are resolved. [FlagRuleType_RequiredIf] compares
+// resolved values, including defaults, like [Flag.Required] does. [FlagRuleType_DependsOn]
+// ignores defaults, like [FlagConstraint] does.
+type FlagRule struct {
+	Type FlagRuleType
+
+	// FlagName is the flag the rule applies to.
+	FlagName string
+
+	// IfFlagName is the flag the rule depends on.
+	IfFlagName string
+
+	// IfValue is compared against IfFlagName's resolved value for [FlagRuleType_RequiredIf].
+	// It must have the same type as the value's Get method returns.
+	IfValue interface{}
+}
+
+// RequiredIf requires flagName to be set when ifFlagName's resolved value equals ifValue.
+// ifValue must have the same type as the flag's value (for example, a string for a scalar.String flag).
+//
+// Example usage:
+//
+//	warg.RequiredIf("--cert", "--mode", "tls")
+func RequiredIf(flagName string, ifFlagName string, ifValue interface{}) CmdOpt {
+	return func(cmd *Cmd) {
+		cmd.Rules = append(cmd.Rules, FlagRule{
+			Type:       FlagRuleType_RequiredIf,
+			FlagName:   flagName,
+			IfFlagName: ifFlagName,
+			IfValue:    ifValue,
+		})
+	}
+}
+
+// DependsOn only allows flagName to be set when ifFlagName is also set. Like [FlagConstraint],
+// values that only come from defaults don't count as set, for either flag.
+//
+// Example usage:
+//
+//	warg.DependsOn("--key-password", "--key-file")
+func DependsOn(flagName string, ifFlagName string) CmdOpt {
+	return func(cmd *Cmd) {
+		cmd.Rules = append(cmd.Rules, FlagRule{
+			Type:       FlagRuleType_DependsOn,
+			FlagName:   flagName,
+			IfFlagName: ifFlagName,
+			IfValue:    nil,
+		})
+	}
+}
+
+// String formats the rule for help output, such as `--cert required if --mode is "tls"`.
+func (fr FlagRule) String() string {
+	switch fr.Type {
+	case FlagRuleType_RequiredIf:
+		return fr.FlagName + " required if " + fr.IfFlagName + " is " + fmt.Sprintf("%#v", fr.IfValue)
+	case FlagRuleType_DependsOn:
+		return fr.FlagName + " requires " + fr.IfFlagName
+	default:
+		return fr.FlagName + " " + string(fr.Type) + " " + fr.IfFlagName
+	}
+}
+
+// check returns an error if the resolved flag values violate the rule.
+func (fr FlagRule) check(flagValues ValueMap, flagSources map[string]FlagSource) error {
+	switch fr.Type {
+	case FlagRuleType_RequiredIf:
+		if !flagValues.IsSet(fr.IfFlagName) || !reflect.DeepEqual(flagValues[fr.IfFlagName].Get(), fr.IfValue) {
+			return nil
+		}
+		if flagValues.IsSet(fr.FlagName) {
+			return nil
+		}
+		return colerr.NewWrappedf(
+			nil,
+			"Flag is required because %s is %s: %s",
+			describeFlagSource(fr.IfFlagName, flagSources),
+			fmt.Sprintf("%#v", fr.IfValue),
+			fr.FlagName,
+		)
+	case FlagRuleType_DependsOn:
+		if !flagSetByUser(flagValues, fr.FlagName) || flagSetByUser(flagValues, fr.IfFlagName) {
+			return nil
+		}
+		return colerr.NewWrappedf(
+			nil,
+			"Flag is only valid when %s is set: %s",
+			fr.IfFlagName,
+			describeFlagSource(fr.FlagName, flagSources),
+		)
+	default:
+		return colerr.NewWrappedf(nil, "Unknown flag rule type: %s", string(fr.Type))
+	}
+}
+
+// validateFlagRules checks that each rule names two different flags that exist on the command
+// or globally, and that RequiredIf values have the same type as their flag's value.
+func validateFlagRules(globalFlags FlagMap, comFlags FlagMap, rules []FlagRule) error {
+	lookup := func(name string) (Flag, bool) {
+		fl, exists := comFlags[name]
+		if !exists {
+			fl, exists = globalFlags[name]
+		}
+		return fl, exists
+	}
+	var errs []error
+	for _, fr := range rules {
+		switch fr.Type {
+		case FlagRuleType_RequiredIf, FlagRuleType_DependsOn:
+		default:
+			errs = append(errs, colerr.NewWrappedf(nil, "Unknown flag rule type: %s", string(fr.Type)))
+			continue
+		}
+		if fr.FlagName == fr.IfFlagName {
+			errs = append(errs, colerr.NewWrappedf(nil, "Flag rules must name two different flags: %s", fr.String()))
+		}
+		if _, exists := lookup(fr.FlagName); !exists {
+			errs = append(errs, colerr.NewWrappedf(nil, "Flag rule names an unknown flag: %s", fmt.Sprintf("%s: %s", fr.String(), fr.FlagName)))
+		}
+		ifFlag, exists := lookup(fr.IfFlagName)
+		if !exists {
+			errs = append(errs, colerr.NewWrappedf(nil, "Flag rule names an unknown flag: %s", fmt.Sprintf("%s: %s", fr.String(), fr.IfFlagName)))
+			continue
+		}
+		if fr.Type == FlagRuleType_RequiredIf && ifFlag.EmptyValueConstructor != nil {
+			expected := reflect.TypeOf(ifFlag.EmptyValueConstructor().Get())
+			actual := reflect.TypeOf(fr.IfValue)
+			if expected != actual {
+				errs = append(errs, colerr.NewWrappedf(
+					nil,
+					"Flag rule value type (%s) doesn't match flag value type (%s): %s",
+					fmt.Sprintf("%v", actual),
+					fmt.Sprintf("%v", expected),
+					fr.String(),
+				))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -13,7 +13,7 @@ import (
 )
 
 // DefaultHelpCmdMap returns the built-in help command implementations: "default", "detailed",
-// "outline", "allcommands", and "compact".
+// "outline", "allcommands", "compact", "explain", and "json".
 func DefaultHelpCmdMap() CmdMap {
 	allCmdsHelp := NewCmd("", buildHelpAction(detailedCmdHelp(), allCmdsSectionHelp()))
 	return CmdMap{
@@ -22,6 +22,8 @@ func DefaultHelpCmdMap() CmdMap {
 		"outline":     outlineHelp(),
 		"allcommands": allCmdsHelp,
 		"compact":     NewCmd("", buildHelpAction(compactCmdHelp(), compactSectionHelp())),
+		"explain":     NewCmd("", explainHelp()),
+		"json":        NewCmd("", jsonHelp()),
 	}
 }
 
@@ -43,7 +43,7 @@ func allCmdsSectionHelp() Action {
 
 		for _, flatSec := range depthFirstSections(*cur, path) {
 
-			for _, name := range flatSec.Sec.Cmds.SortedNames() {
+			for _, name := range flatSec.Sec.Cmds.visibleSortedNames() {
 
 				com := flatSec.Sec.Cmds[name]
 				p.Print("  # ")
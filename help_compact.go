@@ -23,35 +23,41 @@ func compactBuildFlagLine(s *styles.Styles, name string, f *Flag, val value.Valu
 	// Build left column: "  -a, --name type" or "  --name type"
 	var left strings.Builder
 	left.WriteString("  ")
-	if f.Alias != "" {
-		left.WriteString(s.FlagAlias(f.Alias))
+	for _, alias := range f.helpAliases() {
+		left.WriteString(s.FlagAlias(alias))
 		left.WriteString(", ")
 	}
-	left.WriteString(s.FlagName(name))
-	left.WriteString(" ")
-	left.WriteString(val.Description())
+	if f.Negatable {
+		left.WriteString(s.FlagName("--[no-]" + strings.TrimPrefix(name, "--")))
+	} else {
+		left.WriteString(s.FlagName(name))
+	}
+	// switches don't take a value, so don't print a type
+	if !f.Switch {
+		left.WriteString(" ")
+		left.WriteString(val.Description())
+	}
 
 	// Build right column: description + annotations
 	var right strings.Builder
 	right.WriteString(f.HelpShort)
 
 	// Add default value
-	if val.HasDefault() {
-		switch v := val.(type) {
-		case value.ScalarValue:
-			fmt.Fprintf(&right, " [default: %q]", v.DefaultString())
-		case value.SliceValue:
-			fmt.Fprintf(&right, " [default: %v]", v.DefaultStringSlice())
-		case value.DictValue:
-			fmt.Fprintf(&right, " [default: %v]", v.DefaultStringMap())
-		}
-	}
+	compactWriteDefault(&right, val)
 
 	// Add required marker
 	if f.Required {
 		right.WriteString(" [required]")
 	}
 
+	// Add deprecation
+	right.WriteString(deprecatedHelpSuffix(f.Deprecation))
+
+	// Add value constraints
+	if constraints := value.Constraints(val); len(constraints) > 0 {
+		fmt.Fprintf(&right, " [%s]", strings.Join(constraints, ", "))
+	}
+
 	// Add env vars
 	if len(f.EnvVars) > 0 {
 		fmt.Fprintf(&right, " [env: %s]", strings.Join(f.EnvVars, ", "))
@@ -61,17 +67,35 @@ func compactBuildFlagLine(s *styles.Styles, name string, f *Flag, val value.Valu
 	if f.ConfigPath != "" {
 		fmt.Fprintf(&right, " [config: %s]", f.ConfigPath)
 	}
-	if val.UpdatedBy() != value.UpdatedByUnset {
-		fmt.Fprintf(&right, " [setby: %s]", string(val.UpdatedBy()))
-		switch v := val.(type) {
-		case value.ScalarValue:
-			fmt.Fprintf(&right, " [current: %q]", v.String())
-		case value.SliceValue:
-			fmt.Fprintf(&right, " [current: %v]", v.StringSlice())
-		case value.DictValue:
-			fmt.Fprintf(&right, " [current: %v]", v.StringMap())
-		}
+	compactWriteCurrent(&right, val)
+
+	return compactFlagLine{
+		leftCol:  left.String(),
+		rightCol: right.String(),
 	}
+}
+
+// compactBuildPositionalLine constructs the left and right columns for a single positional.
+func compactBuildPositionalLine(s *styles.Styles, pos *Positional, val value.Value) compactFlagLine {
+	var left strings.Builder
+	left.WriteString("  ")
+	left.WriteString(s.FlagName(pos.Name))
+	left.WriteString(" ")
+	left.WriteString(val.Description())
+
+	var right strings.Builder
+	right.WriteString(pos.HelpShort)
+	compactWriteDefault(&right, val)
+	if pos.Required {
+		right.WriteString(" [required]")
+	}
+	if constraints := value.Constraints(val); len(constraints) > 0 {
+		fmt.Fprintf(&right, " [%s]", strings.Join(constraints, ", "))
+	}
+	if pos.Variadic {
+		right.WriteString(" [variadic]")
+	}
+	compactWriteCurrent(&right, val)
 
 	return compactFlagLine{
 		leftCol:  left.String(),
@@ -79,6 +103,37 @@ func compactBuildFlagLine(s *styles.Styles, name string, f *Flag, val value.Valu
 	}
 }
 
+// compactWriteDefault writes a " [default: ...]" annotation if val has a default.
+func compactWriteDefault(right *strings.Builder, val value.Value) {
+	if !val.HasDefault() {
+		return
+	}
+	switch v := val.(type) {
+	case value.ScalarValue:
+		fmt.Fprintf(right, " [default: %q]", v.DefaultString())
+	case value.SliceValue:
+		fmt.Fprintf(right, " [default: %v]", v.DefaultStringSlice())
+	case value.DictValue:
+		fmt.Fprintf(right, " [default: %v]", v.DefaultStringMap())
+	}
+}
+
+// compactWriteCurrent writes " [setby: ...] [current: ...]" annotations if val has been set.
+func compactWriteCurrent(right *strings.Builder, val value.Value) {
+	if val.UpdatedBy() == value.UpdatedByUnset {
+		return
+	}
+	fmt.Fprintf(right, " [setby: %s]", string(val.UpdatedBy()))
+	switch v := val.(type) {
+	case value.ScalarValue:
+		fmt.Fprintf(right, " [current: %q]", v.String())
+	case value.SliceValue:
+		fmt.Fprintf(right, " [current: %v]", v.StringSlice())
+	case value.DictValue:
+		fmt.Fprintf(right, " [current: %v]", v.StringMap())
+	}
+}
+
 // compactVisibleLen returns the visible length of a string, stripping ANSI escape sequences.
 func compactVisibleLen(s string) int {
 	n := 0
@@ -206,11 +261,15 @@ func compactCmdHelp() Action {
 
 		// Usage line
 		p.Printf("%s:\n\n", s.Header("Usage"))
+		usagePath.WriteString(" [flags]")
+		if len(cur.Positionals) > 0 {
+			usagePath.WriteString(" ")
+			usagePath.WriteString(cur.Positionals.usage())
+		}
 		if cur.AllowForwardedArgs {
-			p.Printf("  %s [flags] -- [args]\n", usagePath.String())
-		} else {
-			p.Printf("  %s [flags]\n", usagePath.String())
+			usagePath.WriteString(" -- [args]")
 		}
+		p.Printf("  %s\n", usagePath.String())
 		p.Println()
 
 		// Description
@@ -221,14 +280,32 @@ func compactCmdHelp() Action {
 		}
 		p.Println()
 
+		// Deprecation
+		if cur.Deprecation != nil {
+			p.Printf("%s: %s\n\n", s.ErrorAlt("Deprecated"), cur.Deprecation.Message)
+		}
+
+		// Positional Arguments
+		if len(cur.Positionals) > 0 {
+			var lines []compactFlagLine
+			for i := range cur.Positionals {
+				pos := &cur.Positionals[i]
+				lines = append(lines, compactBuildPositionalLine(&s, pos, cmdCtx.ParseState.PositionalValues[pos.Name]))
+			}
+			p.Printf("%s:\n\n", s.Header("Positional Arguments"))
+			compactPrintFlags(p, lines, termWidth)
+			p.Println()
+		}
+
 		// Command Flags
-		cmdFlags := cmdCtx.ParseState.CurrentCmd.Flags
+		cmdFlags := cmdCtx.App.ownFlags(cmdCtx.ParseState)
 		groups := cmdFlags.groupedNames()
 		hasAnyFlags := false
 		for _, group := range groups {
 			var lines []compactFlagLine
 			for _, name := range group.FlagNames {
 				fl := cmdFlags[name]
+				fl.EnvVars = cmdCtx.App.flagEnvVars(name, fl, cmdCtx.ParseState.cmdPath())
 				val := cmdCtx.ParseState.FlagValues[name]
 				lines = append(lines, compactBuildFlagLine(&s, name, &fl, val))
 			}
@@ -251,6 +328,47 @@ func compactCmdHelp() Action {
 			p.Println()
 		}
 
+		// Flag Constraints
+		if len(cur.Constraints) > 0 || len(cur.Rules) > 0 {
+			p.Printf("%s:\n\n", s.Header("Flag Constraints"))
+			for _, fc := range cur.Constraints {
+				p.Printf("  %s\n", fc.String())
+			}
+			for _, fr := range cur.Rules {
+				p.Printf("  %s\n", fr.String())
+			}
+			p.Println()
+		}
+
+		// Inherited Flags, nearest section first
+		sectionGroups := cmdCtx.App.sectionFlagGroups(cmdCtx.ParseState.SectionPath)
+		for i := len(sectionGroups) - 1; i >= 0; i-- {
+			sg := sectionGroups[i]
+			hasAnySectionFlags := false
+			for _, group := range sg.Flags.groupedNames() {
+				var lines []compactFlagLine
+				for _, name := range group.FlagNames {
+					fl := sg.Flags[name]
+					fl.EnvVars = cmdCtx.App.flagEnvVars(name, fl, sg.Path)
+					val := cmdCtx.ParseState.FlagValues[name]
+					lines = append(lines, compactBuildFlagLine(&s, name, &fl, val))
+				}
+				if len(lines) > 0 {
+					if !hasAnySectionFlags {
+						p.Printf("%s:\n\n", s.Header(cmdCtx.App.inheritedFlagsHeader(sg.Path)))
+					}
+					if group.Name != "" {
+						p.Printf("\n  %s:\n", s.Header(group.Name))
+					}
+					compactPrintFlags(p, lines, termWidth)
+					hasAnySectionFlags = true
+				}
+			}
+			if hasAnySectionFlags {
+				p.Println()
+			}
+		}
+
 		// Global Flags
 		globalGroups := cmdCtx.App.GlobalFlags.groupedNames()
 		hasAnyGlobalFlags := false
@@ -258,6 +376,7 @@ func compactCmdHelp() Action {
 			var lines []compactFlagLine
 			for _, name := range group.FlagNames {
 				fl := cmdCtx.App.GlobalFlags[name]
+				fl.EnvVars = cmdCtx.App.flagEnvVars(name, fl, nil)
 				val := cmdCtx.ParseState.FlagValues[name]
 				lines = append(lines, compactBuildFlagLine(&s, name, &fl, val))
 			}
@@ -323,29 +442,35 @@ func compactSectionHelp() Action {
 		}
 		p.Println()
 
+		// Deprecation
+		if cur.Deprecation != nil {
+			p.Printf("%s: %s\n\n", s.ErrorAlt("Deprecated"), cur.Deprecation.Message)
+		}
+
 		// Available Commands
-		if len(cur.Cmds) > 0 {
+		cmdNames := cur.Cmds.visibleSortedNames()
+		if len(cmdNames) > 0 {
 			p.Printf("%s:\n\n", s.Header("Commands"))
 
 			// Compute max command name length for alignment
 			maxNameLen := 0
-			for _, k := range cur.Cmds.SortedNames() {
-				if len(k) > maxNameLen {
-					maxNameLen = len(k)
+			for _, k := range cmdNames {
+				if l := len(withAliases(k, cur.Cmds[k].Aliases)); l > maxNameLen {
+					maxNameLen = l
 				}
 			}
 
 			const gutter = 3
 			descCol := 2 + maxNameLen + gutter // 2 for indent
 
-			for _, k := range cur.Cmds.SortedNames() {
-				name := s.CommandName(k)
+			for _, k := range cmdNames {
+				name := s.CommandName(withAliases(k, cur.Cmds[k].Aliases))
 				nameVisLen := compactVisibleLen(name)
 				padding := strings.Repeat(" ", descCol-2-nameVisLen+gutter-gutter) // align to descCol from after the indent
 				// Recalculate: indent(2) + name + padding + desc
 				pad := strings.Repeat(" ", 2+maxNameLen+gutter-2-nameVisLen)
 
-				desc := cur.Cmds[string(k)].HelpShort
+				desc := cur.Cmds[string(k)].HelpShort + deprecatedHelpSuffix(cur.Cmds[string(k)].Deprecation)
 				if termWidth > 0 {
 					availWidth := termWidth - descCol
 					if availWidth >= 20 {
@@ -359,25 +484,26 @@ func compactSectionHelp() Action {
 		}
 
 		// Sections (sub-sections)
-		if len(cur.Sections) > 0 {
+		sectionNames := cur.Sections.visibleSortedNames()
+		if len(sectionNames) > 0 {
 			p.Printf("%s:\n\n", s.Header("Sections"))
 
 			maxNameLen := 0
-			for _, k := range cur.Sections.SortedNames() {
-				if len(k) > maxNameLen {
-					maxNameLen = len(k)
+			for _, k := range sectionNames {
+				if l := len(withAliases(k, cur.Sections[k].Aliases)); l > maxNameLen {
+					maxNameLen = l
 				}
 			}
 
 			const gutter = 3
 			descCol := 2 + maxNameLen + gutter
 
-			for _, k := range cur.Sections.SortedNames() {
-				name := s.SectionName(k)
+			for _, k := range sectionNames {
+				name := s.SectionName(withAliases(k, cur.Sections[k].Aliases))
 				nameVisLen := compactVisibleLen(name)
 				pad := strings.Repeat(" ", 2+maxNameLen+gutter-2-nameVisLen)
 
-				desc := cur.Sections[k].HelpShort
+				desc := cur.Sections[k].HelpShort + deprecatedHelpSuffix(cur.Sections[k].Deprecation)
 				if termWidth > 0 {
 					availWidth := termWidth - descCol
 					if availWidth >= 20 {
@@ -6,26 +6,22 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"strings"
 
 	"go.bbkane.com/warg/styles"
 	"go.bbkane.com/warg/value"
 )
 
 func detailedPrintFlag(p *styles.Printer, s *styles.Styles, name string, f *Flag, val value.Value) {
-	if f.Alias != "" {
-		p.Printf(
-			"  %s , %s : %s\n",
-			s.FlagName(name),
-			s.FlagAlias(f.Alias),
-			f.HelpShort,
-		)
-	} else {
-		p.Printf(
-			"  %s : %s\n",
-			s.FlagName(name),
-			f.HelpShort,
-		)
+	names := s.FlagName(name)
+	for _, alias := range f.helpAliases() {
+		names += " , " + s.FlagAlias(alias)
 	}
+	p.Printf(
+		"  %s : %s\n",
+		names,
+		f.HelpShort,
+	)
 	p.Printf(
 		"    %s : %s\n",
 		s.Label("type"),
@@ -39,7 +35,73 @@ func detailedPrintFlag(p *styles.Printer, s *styles.Styles, name string, f *Flag
 			val.Choices(),
 		)
 	}
+	if constraints := value.Constraints(val); len(constraints) > 0 {
+		p.Printf(
+			"    %s : %s\n",
+			s.Label("constraints"),
+			strings.Join(constraints, ", "),
+		)
+	}
+
+	detailedPrintDefault(p, s, val)
+	if f.ConfigPath != "" {
+		p.Printf(
+			"    %s : %s\n",
+			s.Label("configpath"),
+			f.ConfigPath,
+		)
+	}
+	if len(f.EnvVars) > 0 {
+		p.Printf(
+			"    %s : %s\n",
+			s.Label("envvars"),
+			f.EnvVars,
+		)
+	}
+
+	if f.Deprecation != nil {
+		p.Printf(
+			"    %s : %s\n",
+			s.Label("deprecated"),
+			f.Deprecation.Message,
+		)
+	}
+
+	// TODO: it would be nice if this were red when the value isn't set
+	if f.Required {
+		p.Printf(
+			"    %s : true\n",
+			s.Label("required"),
+		)
+	}
+	if f.Switch {
+		p.Printf(
+			"    %s : true\n",
+			s.Label("switch"),
+		)
+	}
+	if f.Negatable {
+		p.Printf(
+			"    %s : %s\n",
+			s.Label("negation"),
+			negatedName(name),
+		)
+	}
+	if f.UnsetSentinel != nil {
+		p.Printf(
+			"    %s : %s\n",
+			s.Label("unsetsentinel"),
+			*f.UnsetSentinel,
+		)
+	}
 
+	detailedPrintCurrentValue(p, s, val)
+
+	p.Println()
+}
+
+// detailedPrintDefault prints val's default value, if it has one.
+func detailedPrintDefault(p *styles.Printer, s *styles.Styles, val value.Value) {
 	if val.HasDefault() {
 		switch v := val.(type) {
 		case value.DictValue:
@@ -71,36 +133,10 @@ func detailedPrintFlag(p *styles.Printer, s *styles.Styles, name string, f *Flag
 			panic(fmt.Sprintf("Unexpected type: %#v", val))
 		}
 	}
-	if f.ConfigPath != "" {
-		p.Printf(
-			"    %s : %s\n",
-			s.Label("configpath"),
-			f.ConfigPath,
-		)
-	}
-	if len(f.EnvVars) > 0 {
-		p.Printf(
-			"    %s : %s\n",
-			s.Label("envvars"),
-			f.EnvVars,
-		)
-	}
-
-	// TODO: it would be nice if this were red when the value isn't set
-	if f.Required {
-		p.Printf(
-			"    %s : true\n",
-			s.Label("required"),
-		)
-	}
-	if f.UnsetSentinel != nil {
-		p.Printf(
-			"    %s : %s\n",
-			s.Label("unsetsentinel"),
-			*f.UnsetSentinel,
-		)
-	}
+}
 
+// detailedPrintCurrentValue prints val's current value and its source, if it has been set.
+func detailedPrintCurrentValue(p *styles.Printer, s *styles.Styles, val value.Value) {
 	if val.UpdatedBy() != value.UpdatedByUnset {
 		switch v := val.(type) {
 		case value.DictValue:
@@ -149,9 +185,54 @@ func detailedPrintFlag(p *styles.Printer, s *styles.Styles, name string, f *Flag
 				v.String(),
 			)
 		default:
-			panic(fmt.Sprintf("unexpected value: %#v", f))
+			panic(fmt.Sprintf("unexpected value: %#v", val))
 		}
 	}
+}
+
+func detailedPrintPositional(p *styles.Printer, s *styles.Styles, pos *Positional, val value.Value) {
+	p.Printf(
+		"  %s : %s\n",
+		s.FlagName(pos.Name),
+		pos.HelpShort,
+	)
+	p.Printf(
+		"    %s : %s\n",
+		s.Label("type"),
+		val.Description(),
+	)
+
+	if len(val.Choices()) > 0 {
+		p.Printf(
+			"    %s : %s\n",
+			s.Label("choices"),
+			val.Choices(),
+		)
+	}
+	if constraints := value.Constraints(val); len(constraints) > 0 {
+		p.Printf(
+			"    %s : %s\n",
+			s.Label("constraints"),
+			strings.Join(constraints, ", "),
+		)
+	}
+
+	detailedPrintDefault(p, s, val)
+
+	if pos.Required {
+		p.Printf(
+			"    %s : true\n",
+			s.Label("required"),
+		)
+	}
+	if pos.Variadic {
+		p.Printf(
+			"    %s : true\n",
+			s.Label("variadic"),
+		)
+	}
+
+	detailedPrintCurrentValue(p, s, val)
 
 	p.Println()
 }
@@ -181,9 +262,23 @@ func detailedCmdHelp() Action {
 
 		p.Println()
 
+		if cur.Deprecation != nil {
+			p.Printf("%s: %s\n\n", s.ErrorAlt("Deprecated"), cur.Deprecation.Message)
+		}
+
+		if len(cur.Positionals) > 0 {
+			p.Println(s.Header("Positional Arguments") + ":")
+			p.Println()
+			for i := range cur.Positionals {
+				pos := &cur.Positionals[i]
+				detailedPrintPositional(p, &s, pos, cmdCtx.ParseState.PositionalValues[pos.Name])
+			}
+		}
+
 		// compute sections for command flags and inherited flags,
 		// then print their headers and them if they're not empty
 		var commandFlagHelp bytes.Buffer
+		var inheritedFlagHelp bytes.Buffer
 		var sectionFlagHelp bytes.Buffer
 		{
 			globalGroups := cmdCtx.App.GlobalFlags.groupedNames()
@@ -193,12 +288,35 @@ func detailedCmdHelp() Action {
 				}
 				for _, name := range group.FlagNames {
 					f := cmdCtx.App.GlobalFlags[name]
+					f.EnvVars = cmdCtx.App.flagEnvVars(name, f, nil)
 					val := cmdCtx.ParseState.FlagValues[name]
 					detailedPrintFlag(styles.NewPrinter(&sectionFlagHelp), &s, name, &f, val)
 				}
 			}
 
-			cmdFlags := cmdCtx.ParseState.CurrentCmd.Flags
+			// inherited flags, nearest section first
+			sectionGroups := cmdCtx.App.sectionFlagGroups(cmdCtx.ParseState.SectionPath)
+			for i := len(sectionGroups) - 1; i >= 0; i-- {
+				sg := sectionGroups[i]
+				var groupHelp bytes.Buffer
+				for _, group := range sg.Flags.groupedNames() {
+					if group.Name != "" {
+						fmt.Fprintf(&groupHelp, "  %s:\n\n", s.Header(group.Name))
+					}
+					for _, name := range group.FlagNames {
+						f := sg.Flags[name]
+						f.EnvVars = cmdCtx.App.flagEnvVars(name, f, sg.Path)
+						val := cmdCtx.ParseState.FlagValues[name]
+						detailedPrintFlag(styles.NewPrinter(&groupHelp), &s, name, &f, val)
+					}
+				}
+				if groupHelp.Len() > 0 {
+					fmt.Fprintf(&inheritedFlagHelp, "%s:\n\n", s.Header(cmdCtx.App.inheritedFlagsHeader(sg.Path)))
+					_, _ = groupHelp.WriteTo(&inheritedFlagHelp)
+				}
+			}
+
+			cmdFlags := cmdCtx.App.ownFlags(cmdCtx.ParseState)
 			groups := cmdFlags.groupedNames()
 			for _, group := range groups {
 				if group.Name != "" {
@@ -206,6 +324,7 @@ func detailedCmdHelp() Action {
 				}
 				for _, name := range group.FlagNames {
 					f := cmdFlags[name]
+					f.EnvVars = cmdCtx.App.flagEnvVars(name, f, cmdCtx.ParseState.cmdPath())
 					val := cmdCtx.ParseState.FlagValues[name]
 					detailedPrintFlag(styles.NewPrinter(&commandFlagHelp), &s, name, &f, val)
 				}
@@ -216,6 +335,18 @@ func detailedCmdHelp() Action {
 				p.Println()
 				_, _ = commandFlagHelp.WriteTo(f)
 			}
+			if len(cur.Constraints) > 0 || len(cur.Rules) > 0 {
+				p.Println(s.Header("Flag Constraints") + ":")
+				p.Println()
+				for _, fc := range cur.Constraints {
+					p.Printf("  %s\n", fc.String())
+				}
+				for _, fr := range cur.Rules {
+					p.Printf("  %s\n", fr.String())
+				}
+				p.Println()
+			}
+			_, _ = inheritedFlagHelp.WriteTo(f)
 			if sectionFlagHelp.Len() > 0 {
 				p.Println(s.Header("Global Flags") + ":")
 				p.Println()
@@ -263,16 +394,26 @@ func detailedSectionHelp() Action {
 
 		p.Println()
 
+		if cur.Deprecation != nil {
+			p.Printf("%s: %s\n\n", s.ErrorAlt("Deprecated"), cur.Deprecation.Message)
+		}
+
 		// Print sections
-		if len(cur.Sections) > 0 {
+		sectionNames := cur.Sections.visibleSortedNames()
+		if len(sectionNames) > 0 {
 			p.Println(s.Header("Sections") + ":")
 			p.Println()
 
-			for _, k := range cur.Sections.SortedNames() {
+			for _, k := range sectionNames {
+				names := s.SectionName(k)
+				for _, alias := range cur.Sections[k].Aliases {
+					names += " , " + s.SectionName(alias)
+				}
 				p.Printf(
-					"  %s : %s\n",
-					s.SectionName(k),
+					"  %s : %s%s\n",
+					names,
 					cur.Sections[k].HelpShort,
+					deprecatedHelpSuffix(cur.Sections[k].Deprecation),
 				)
 			}
 
@@ -280,15 +421,21 @@ func detailedSectionHelp() Action {
 		}
 
 		// Print commands
-		if len(cur.Cmds) > 0 {
+		cmdNames := cur.Cmds.visibleSortedNames()
+		if len(cmdNames) > 0 {
 			p.Println(s.Header("Commands") + ":")
 			p.Println()
 
-			for _, k := range cur.Cmds.SortedNames() {
+			for _, k := range cmdNames {
+				names := s.CommandName(k)
+				for _, alias := range cur.Cmds[string(k)].Aliases {
+					names += " , " + s.CommandName(alias)
+				}
 				p.Printf(
-					"  %s : %s\n",
-					s.CommandName(k),
+					"  %s : %s%s\n",
+					names,
 					cur.Cmds[string(k)].HelpShort,
+					deprecatedHelpSuffix(cur.Cmds[string(k)].Deprecation),
 				)
 			}
 		}
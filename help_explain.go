@@ -0,0 +1,115 @@
+package warg
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.bbkane.com/warg/styles"
+	"go.bbkane.com/warg/value"
+)
+
+// explainValueString formats a flag's current value like compact help's [current: ...] annotation.
+func explainValueString(val value.Value) string {
+	if val.UpdatedBy() == value.UpdatedByUnset {
+		return ""
+	}
+	switch v := val.(type) {
+	case value.ScalarValue:
+		return fmt.Sprintf("%q", v.String())
+	case value.SliceValue:
+		return fmt.Sprintf("%v", v.StringSlice())
+	case value.DictValue:
+		return fmt.Sprintf("%v", v.StringMap())
+	default:
+		return fmt.Sprintf("%v", val.Get())
+	}
+}
+
+// explainHelp prints a table of every resolved flag, where its value came from, and the value itself,
+// followed by a table of the command's positionals.
+// Use it to debug "why is this flag set?".
+func explainHelp() Action {
+	return func(cmdCtx CmdContext) error {
+		file := cmdCtx.Stdout
+		f := bufio.NewWriter(file)
+		defer f.Flush()
+
+		s, err := conditionallyEnableStyle(false, cmdCtx.Flags, file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error enabling color. Continuing without: %v\n", err)
+		}
+
+		p := styles.NewPrinter(f)
+
+		ps := cmdCtx.ParseState
+		path := append([]string{cmdCtx.App.Name}, ps.SectionPath...)
+		if ps.CurrentCmdName != "" {
+			path = append(path, ps.CurrentCmdName)
+		}
+		p.Println(s.Header("Flag sources for") + ": " + strings.Join(path, " "))
+		p.Println()
+
+		flagNames := make([]string, 0, len(ps.FlagValues))
+		flagNames = append(flagNames, cmdCtx.App.GlobalFlags.SortedNames()...)
+		if ps.CurrentCmd != nil {
+			flagNames = append(flagNames, ps.CurrentCmd.Flags.SortedNames()...)
+		}
+
+		rows := [][]string{{"FLAG", "SOURCE", "ORIGIN", "VALUE"}}
+		for _, name := range flagNames {
+			rows = append(rows, explainRow(name, ps.FlagValues[name], ps.FlagSources[name]))
+		}
+		explainWriteTable(p, &s, rows)
+
+		if ps.CurrentCmd != nil && len(ps.CurrentCmd.Positionals) > 0 {
+			rows = [][]string{{"POSITIONAL", "SOURCE", "ORIGIN", "VALUE"}}
+			for _, pos := range ps.CurrentCmd.Positionals {
+				rows = append(rows, explainRow(pos.Name, ps.PositionalValues[pos.Name], ps.PositionalSources[pos.Name]))
+			}
+			p.Println()
+			explainWriteTable(p, &s, rows)
+		}
+		return nil
+	}
+}
+
+// explainRow returns the table row for a flag or positional.
+func explainRow(name string, val value.Value, source FlagSource) []string {
+	updatedBy := string(val.UpdatedBy())
+	if val.UpdatedBy() == value.UpdatedByUnset {
+		updatedBy = "unset"
+	}
+	return []string{name, updatedBy, source.Origin(), explainValueString(val)}
+}
+
+// explainWriteTable prints rows aligned in columns. The first row is the header.
+func explainWriteTable(p *styles.Printer, s *styles.Styles, rows [][]string) {
+	// pad the plain text so styling doesn't affect alignment
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+	for rowIdx, row := range rows {
+		var line strings.Builder
+		line.WriteString("  ")
+		for i, cell := range row {
+			padded := cell
+			if i < len(row)-1 {
+				padded += strings.Repeat(" ", widths[i]-len(cell)+2)
+			}
+			switch {
+			case rowIdx == 0:
+				line.WriteString(s.Label(padded))
+			case i == 0:
+				line.WriteString(s.FlagName(cell) + padded[len(cell):])
+			default:
+				line.WriteString(padded)
+			}
+		}
+		p.Println(strings.TrimRight(line.String(), " "))
+	}
+}
@@ -0,0 +1,22 @@
+package warg
+
+import (
+	"encoding/json"
+
+	"go.bbkane.com/warg/colerr"
+)
+
+// jsonHelp prints [App.Describe] as indented JSON.
+func jsonHelp() Action {
+	return func(cmdCtx CmdContext) error {
+		b, err := json.MarshalIndent(cmdCtx.App.Describe(), "", "  ")
+		if err != nil {
+			return colerr.NewWrapped(err, "Could not marshal app description")
+		}
+		_, err = cmdCtx.Stdout.Write(append(b, '\n'))
+		if err != nil {
+			return colerr.NewWrapped(err, "Could not write app description")
+		}
+		return nil
+	}
+}
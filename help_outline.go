@@ -10,14 +10,14 @@ import (
 
 func outlineHelper(p *styles.Printer, s *styles.Styles, sec Section, indent int) {
 	// commands and command flags
-	for _, comName := range sec.Cmds.SortedNames() {
+	for _, comName := range sec.Cmds.visibleSortedNames() {
 		p.Println(
 			leftPad(s.CommandName(string(comName)), "  ", indent),
 		)
 	}
 
 	// sections
-	for _, k := range sec.Sections.SortedNames() {
+	for _, k := range sec.Sections.visibleSortedNames() {
 		childSec := sec.Sections[k]
 		p.Println(
 			leftPad(s.SectionName(k), "  ", indent),
@@ -0,0 +1,120 @@
+package warg
+
+// Middleware wraps an [Action] with behavior that runs around it, such as logging setup, timing,
+// or auth checks. It should call next to run the wrapped action (or return early to skip it).
+type Middleware func(next Action) Action
+
+// AfterFunc runs after an [Action] with the action's error (nil on success). The error it returns
+// replaces the action's error, so return actionErr to pass it through.
+type AfterFunc func(cmdCtx CmdContext, actionErr error) error
+
+// beforeMiddleware runs before and only runs the action if before succeeds.
+func beforeMiddleware(before Action) Middleware {
+	return func(next Action) Action {
+		return func(cmdCtx CmdContext) error {
+			err := before(cmdCtx)
+			if err != nil {
+				return err
+			}
+			return next(cmdCtx)
+		}
+	}
+}
+
+// afterMiddleware runs after once the action returns, even if it failed.
+func afterMiddleware(after AfterFunc) Middleware {
+	return func(next Action) Action {
+		return func(cmdCtx CmdContext) error {
+			return after(cmdCtx, next(cmdCtx))
+		}
+	}
+}
+
+// Wrap adds a middleware around every command in the app.
+//
+// Middlewares are inherited down the section tree: app middlewares run outside section middlewares,
+// parent section middlewares run outside child section middlewares, and command middlewares run
+// innermost. Within one app, section, or command, middlewares added first run outermost.
+// Middlewares don't run for --help or for the built-in commands (see [CmdSkipInheritedMiddlewares]).
+//
+// Example usage:
+//
+//	warg.Wrap(func(next warg.Action) warg.Action {
+//		return func(cmdCtx warg.CmdContext) error {
+//			start := time.Now()
+//			err := next(cmdCtx)
+//			fmt.Fprintf(cmdCtx.Stderr, "took %s\n", time.Since(start))
+//			return err
+//		}
+//	})
+func Wrap(mw Middleware) AppOpt {
+	return func(a *App) {
+		a.RootSection.Middlewares = append(a.RootSection.Middlewares, mw)
+	}
+}
+
+// Before runs before every command in the app. If it returns an error, the command doesn't run. See [Wrap] for ordering.
+func Before(before Action) AppOpt {
+	return Wrap(beforeMiddleware(before))
+}
+
+// After runs after every command in the app, even if the command failed. See [Wrap] for ordering.
+func After(after AfterFunc) AppOpt {
+	return Wrap(afterMiddleware(after))
+}
+
+// SectionWrap adds a middleware around every command in the section and its subsections. See [Wrap] for ordering.
+func SectionWrap(mw Middleware) SectionOpt {
+	return func(sec *Section) {
+		sec.Middlewares = append(sec.Middlewares, mw)
+	}
+}
+
+// SectionBefore runs before every command in the section and its subsections. If it returns an error, the command doesn't run.
+func SectionBefore(before Action) SectionOpt {
+	return SectionWrap(beforeMiddleware(before))
+}
+
+// SectionAfter runs after every command in the section and its subsections, even if the command failed.
+func SectionAfter(after AfterFunc) SectionOpt {
+	return SectionWrap(afterMiddleware(after))
+}
+
+// CmdWrap adds a middleware around the command. It runs inside any app or section middlewares.
+func CmdWrap(mw Middleware) CmdOpt {
+	return func(cmd *Cmd) {
+		cmd.Middlewares = append(cmd.Middlewares, mw)
+	}
+}
+
+// CmdBefore runs before the command. If it returns an error, the command doesn't run.
+func CmdBefore(before Action) CmdOpt {
+	return CmdWrap(beforeMiddleware(before))
+}
+
+// CmdAfter runs after the command, even if it failed.
+func CmdAfter(after AfterFunc) CmdOpt {
+	return CmdWrap(afterMiddleware(after))
+}
+
+// wrappedAction returns the current command's action wrapped in the middlewares of the root section,
+// each section in the path, and the command, outermost first. Section middlewares are skipped for
+// commands with [Cmd.SkipInheritedMiddlewares].
+func (app *App) wrappedAction(ps *ParseState) Action {
+	var middlewares []Middleware
+	if !ps.CurrentCmd.SkipInheritedMiddlewares {
+		middlewares = append(middlewares, app.RootSection.Middlewares...)
+		sec := app.RootSection
+		for _, name := range ps.SectionPath {
+			sec = sec.Sections[name]
+			middlewares = append(middlewares, sec.Middlewares...)
+		}
+	}
+	middlewares = append(middlewares, ps.CurrentCmd.Middlewares...)
+
+	action := ps.CurrentCmd.Action
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		action = middlewares[i](action)
+	}
+	return action
+}
@@ -0,0 +1,44 @@
+package warg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestREPLHooks(t *testing.T) {
+	var calls []string
+	app := New(
+		"newAppName", "v1.0.0",
+		NewSection(
+			"help for test",
+			NewSubCmd("status", "status", func(CmdContext) error {
+				calls = append(calls, "status")
+				return nil
+			}),
+		),
+		Before(func(CmdContext) error {
+			calls = append(calls, "before")
+			return nil
+		}),
+		After(func(_ CmdContext, actionErr error) error {
+			calls = append(calls, "after")
+			return actionErr
+		}),
+		SkipCompletionCmds(),
+	)
+
+	pr, err := app.Parse([]string{"repl"}, ParseWithLookupEnv(LookupMap(nil)))
+	require.Nil(t, err)
+
+	// the repl command itself doesn't run app hooks. Swap its action so this doesn't start reading stdin.
+	pr.Context.ParseState.CurrentCmd.Action = func(CmdContext) error { return nil }
+	err = app.wrappedAction(pr.Context.ParseState)(pr.Context)
+	require.Nil(t, err)
+	require.Nil(t, calls)
+
+	// each command run inside it runs them exactly once
+	replRunLine(pr.Context, "status")
+	replRunLine(pr.Context, "status")
+	require.Equal(t, []string{"before", "status", "after", "before", "status", "after"}, calls)
+}
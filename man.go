@@ -0,0 +1,356 @@
+package warg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.bbkane.com/warg/colerr"
+	"go.bbkane.com/warg/value/scalar"
+)
+
+// ManPage is a roff man page generated by [App.ManPages].
+type ManPage struct {
+	// FileName is the page's file name, such as "myapp-db-migrate.1"
+	FileName string
+	// Content is the page in roff format
+	Content string
+}
+
+// ManCmd adds an opt-in "man" command that writes man pages for the app and each command
+// (see [App.WriteManPages]) to the directory passed with --output-dir.
+func ManCmd() AppOpt {
+	return func(a *App) {
+		a.ManCmd = true
+	}
+}
+
+func manCmd() SectionOpt {
+	return NewSubCmd(
+		"man",
+		"Write man pages for this app",
+		manCmdAction,
+		CmdSkipInheritedMiddlewares(),
+		CmdHelpLong("Write a roff man page for the app and one for each command to the output directory. Install them somewhere on MANPATH, such as /usr/local/share/man/man1."),
+		NewCmdFlag(
+			"--output-dir",
+			"Directory to write man pages to",
+			scalar.String(
+				scalar.Default("."),
+			),
+			Alias("-o"),
+		),
+	)
+}
+
+func manCmdAction(cmdCtx CmdContext) error {
+	dir := cmdCtx.Flags["--output-dir"].(string)
+	err := cmdCtx.App.WriteManPages(dir)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmdCtx.Stdout, "Wrote man pages to %s\n", dir)
+	return nil
+}
+
+// WriteManPages writes the pages from [App.ManPages] to dir, creating it if needed.
+func (app *App) WriteManPages(dir string) error {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return colerr.NewWrappedf(err, "Could not create man page directory: %s", dir)
+	}
+	for _, page := range app.ManPages() {
+		filePath := filepath.Join(dir, page.FileName)
+		err := os.WriteFile(filePath, []byte(page.Content), 0644)
+		if err != nil {
+			return colerr.NewWrappedf(err, "Could not write man page: %s", filePath)
+		}
+	}
+	return nil
+}
+
+// ManPages generates section 1 man pages: one for the app (listing every command and the global
+// flags) and one per command, in the same order as help (see depthFirstSections).
+// Pages are built from [App.Describe], so hidden sections, commands, and flags are skipped.
+func (app *App) ManPages() []ManPage {
+	desc := app.Describe()
+	var cmdPages []ManPage
+	var cmdListing []manCmdListing
+	for _, flatSec := range depthFirstSections(app.RootSection, nil) {
+		ds := desc.section(flatSec.Path)
+		for _, cmd := range ds.Sec().Cmds {
+			cmdPath := ds.ChildPath(cmd.Name)
+			cmdListing = append(cmdListing, manCmdListing{Path: cmdPath, Cmd: cmd})
+			cmdPages = append(cmdPages, app.cmdManPage(ds, cmd))
+		}
+	}
+	return append([]ManPage{app.appManPage(desc, cmdListing)}, cmdPages...)
+}
+
+// manCmdListing is a command listed on the app's page.
+type manCmdListing struct {
+	Path []string
+	Cmd  CmdDescription
+}
+
+// manPageName returns the man page name for a command path, such as "myapp-db-migrate", or the app name for an empty path.
+func (app *App) manPageName(path []string) string {
+	return strings.Join(append([]string{app.Name}, path...), "-")
+}
+
+// manWriteHeader writes the title and NAME sections.
+func (app *App) manWriteHeader(b *strings.Builder, pageName string, helpShort string) {
+	fmt.Fprintf(b, ".TH %s 1 \"\" %s \"User Commands\"\n", roffQuote(strings.ToUpper(pageName)), roffQuote(app.Name+" "+app.Version))
+	b.WriteString(".SH NAME\n")
+	b.WriteString(roffEscape(pageName) + " \\- " + roffEscape(helpShort) + "\n")
+}
+
+// appManPage generates the app's page, listing cmds.
+func (app *App) appManPage(desc AppDescription, cmds []manCmdListing) ManPage {
+	var b strings.Builder
+	pageName := app.manPageName(nil)
+	root := desc.RootSection
+	app.manWriteHeader(&b, pageName, root.HelpShort)
+
+	b.WriteString(".SH SYNOPSIS\n")
+	b.WriteString(".B " + roffEscape(app.Name) + "\n")
+	b.WriteString("[\\fIsection\\fR...] \\fIcommand\\fR [\\fIflags\\fR]\n")
+
+	b.WriteString(".SH DESCRIPTION\n")
+	b.WriteString(roffText(helpLongOrShort(root.HelpLong, root.HelpShort)))
+
+	if len(cmds) > 0 {
+		b.WriteString(".SH COMMANDS\n")
+		for _, listing := range cmds {
+			b.WriteString(".TP\n")
+			b.WriteString("\\fB" + roffEscape(strings.Join(append([]string{app.Name}, listing.Path...), " ")) + "\\fR\n")
+			b.WriteString(roffEscape(listing.Cmd.HelpShort+listing.Cmd.Deprecation.helpSuffix()) + "\n")
+		}
+	}
+
+	manWriteFlags(&b, "GLOBAL FLAGS", desc.GlobalFlags)
+
+	if root.Footer != "" {
+		b.WriteString(".SH NOTES\n")
+		b.WriteString(roffText(root.Footer))
+	}
+
+	if len(cmds) > 0 {
+		b.WriteString(".SH SEE ALSO\n")
+		refs := make([]string, 0, len(cmds))
+		for _, listing := range cmds {
+			refs = append(refs, "\\fB"+roffEscape(app.manPageName(listing.Path))+"\\fR(1)")
+		}
+		b.WriteString(strings.Join(refs, ",\n") + "\n")
+	}
+
+	return ManPage{FileName: pageName + ".1", Content: b.String()}
+}
+
+// cmdManPage generates the page for cmd in the section ds.
+func (app *App) cmdManPage(ds describedSection, cmd CmdDescription) ManPage {
+	var b strings.Builder
+	cmdPath := ds.ChildPath(cmd.Name)
+	pageName := app.manPageName(cmdPath)
+	app.manWriteHeader(&b, pageName, cmd.HelpShort)
+
+	b.WriteString(".SH SYNOPSIS\n")
+	b.WriteString(".B " + roffEscape(strings.Join(append([]string{app.Name}, cmdPath...), " ")) + "\n")
+	synopsis := "[\\fIflags\\fR]"
+	if len(cmd.Positionals) > 0 {
+		synopsis += " " + roffEscape(positionalsUsage(cmd.Positionals))
+	}
+	if cmd.AllowForwardedArgs {
+		synopsis += " \\-\\- [\\fIargs\\fR]"
+	}
+	b.WriteString(synopsis + "\n")
+
+	b.WriteString(".SH DESCRIPTION\n")
+	b.WriteString(roffText(helpLongOrShort(cmd.HelpLong, cmd.HelpShort)))
+	// Chain[0] is the root section, which can't be deprecated
+	for _, sec := range ds.Chain[1:] {
+		if sec.Deprecation != nil {
+			b.WriteString(".PP\n")
+			b.WriteString(roffEscape("Deprecated: section "+sec.Name+": "+sec.Deprecation.Message) + "\n")
+		}
+	}
+	if cmd.Deprecation != nil {
+		b.WriteString(".PP\n")
+		b.WriteString(roffEscape("Deprecated: "+cmd.Deprecation.Message) + "\n")
+	}
+
+	if len(cmd.Positionals) > 0 {
+		b.WriteString(".SH ARGUMENTS\n")
+		for _, pos := range cmd.Positionals {
+			b.WriteString(".TP\n")
+			b.WriteString("\\fB" + roffEscape(pos.Name) + "\\fR \\fI" + roffEscape(pos.Type) + "\\fR\n")
+			b.WriteString(roffEscape(pos.HelpShort) + "\n")
+			if pos.Required {
+				b.WriteString(".br\nRequired.\n")
+			}
+			manWriteValueDetails(&b, pos.ValueDescription)
+		}
+	}
+
+	manWriteFlags(&b, "FLAGS", cmd.Flags)
+
+	for _, inherited := range ds.InheritedFlags() {
+		manWriteFlags(&b, "INHERITED FLAGS ("+strings.Join(append([]string{app.Name}, inherited.Path...), " ")+")", inherited.Flags)
+	}
+
+	if len(cmd.Constraints) > 0 {
+		b.WriteString(".SH FLAG CONSTRAINTS\n")
+		for _, constraint := range cmd.Constraints {
+			b.WriteString(".IP \\(bu 2\n" + roffEscape(constraint) + "\n")
+		}
+	}
+
+	if cmd.Footer != "" {
+		b.WriteString(".SH NOTES\n")
+		b.WriteString(roffText(cmd.Footer))
+	}
+
+	b.WriteString(".SH SEE ALSO\n")
+	b.WriteString("\\fB" + roffEscape(app.Name) + "\\fR(1) for global flags\n")
+
+	return ManPage{FileName: pageName + ".1", Content: b.String()}
+}
+
+// manWriteFlags writes a section titled title listing flags, with a subsection per [FlagGroup].
+func manWriteFlags(b *strings.Builder, title string, flags []FlagDescription) {
+	groups := groupFlags(flags)
+	if len(groups) == 0 {
+		return
+	}
+	b.WriteString(".SH " + roffQuote(title) + "\n")
+	for _, group := range groups {
+		if group.Name != "" {
+			b.WriteString(".SS " + roffQuote(group.Name) + "\n")
+		}
+		for _, fd := range group.Flags {
+			manWriteFlag(b, fd)
+		}
+	}
+}
+
+// manWriteFlag writes a tagged paragraph describing one flag.
+func manWriteFlag(b *strings.Builder, fd FlagDescription) {
+	names := make([]string, 0, len(fd.Aliases)+1)
+	if fd.Negatable {
+		names = append(names, "\\fB"+roffEscape("--[no-]"+strings.TrimPrefix(fd.Name, "--"))+"\\fR")
+	} else {
+		names = append(names, "\\fB"+roffEscape(fd.Name)+"\\fR")
+	}
+	for _, alias := range fd.Aliases {
+		names = append(names, "\\fB"+roffEscape(alias)+"\\fR")
+	}
+	tag := strings.Join(names, ", ")
+	// switches don't take a value, so don't print a type
+	if !fd.Switch {
+		tag += " \\fI" + roffEscape(fd.Type) + "\\fR"
+	}
+
+	b.WriteString(".TP\n")
+	b.WriteString(tag + "\n")
+	b.WriteString(roffEscape(fd.HelpShort) + "\n")
+	if fd.Required {
+		b.WriteString(".br\nRequired.\n")
+	}
+	if fd.Deprecation != nil {
+		b.WriteString(".br\n" + roffEscape("Deprecated: "+fd.Deprecation.Message) + "\n")
+	}
+	manWriteValueDetails(b, fd.ValueDescription)
+	if len(fd.EnvVars) > 0 {
+		b.WriteString(".br\n" + roffEscape("Environment variables: "+strings.Join(fd.EnvVars, ", ")) + "\n")
+	}
+	if fd.ConfigPath != "" {
+		b.WriteString(".br\n" + roffEscape("Config path: "+fd.ConfigPath) + "\n")
+	}
+}
+
+// manWriteValueDetails writes the value's choices, constraints, and default, if it has them.
+func manWriteValueDetails(b *strings.Builder, vd ValueDescription) {
+	if len(vd.Choices) > 0 {
+		b.WriteString(".br\n" + roffEscape("Choices: "+strings.Join(vd.Choices, ", ")) + "\n")
+	}
+	if len(vd.Constraints) > 0 {
+		b.WriteString(".br\n" + roffEscape("Constraints: "+strings.Join(vd.Constraints, ", ")) + "\n")
+	}
+	if def, ok := vd.defaultString(); ok {
+		b.WriteString(".br\n" + roffEscape("Default: "+def) + "\n")
+	}
+}
+
+// helpLongOrShort returns helpLong if set, and helpShort otherwise.
+func helpLongOrShort(helpLong string, helpShort string) string {
+	if helpLong != "" {
+		return helpLong
+	}
+	return helpShort
+}
+
+// roffEscape escapes backslashes and hyphens, and protects a leading "." or "'" from being read as a request.
+func roffEscape(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\e")
+	s = strings.ReplaceAll(s, "-", "\\-")
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = "\\&" + s
+	}
+	return s
+}
+
+// roffQuote escapes s and wraps it in double quotes for use as a macro argument.
+func roffQuote(s string) string {
+	return "\"" + strings.ReplaceAll(roffEscape(s), "\"", "\\(dq") + "\""
+}
+
+// roffText converts multi-line help text to roff: lines are escaped, blank lines start new paragraphs,
+// and indented blocks (such as examples) are printed indented and unfilled so their line breaks are kept.
+func roffText(s string) string {
+	lines := strings.Split(strings.Trim(s, "\n"), "\n")
+	var b strings.Builder
+	inBlock := false
+	blockIndent := ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		indented := trimmed != "" && (line[0] == ' ' || line[0] == '\t')
+
+		switch {
+		case trimmed == "" && inBlock && nextLineIndented(lines[i+1:]):
+			b.WriteString("\n")
+		case trimmed == "" && inBlock:
+			b.WriteString(".fi\n.RE\n.PP\n")
+			inBlock = false
+		case trimmed == "":
+			b.WriteString(".PP\n")
+		case indented && !inBlock:
+			blockIndent = line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+			b.WriteString(".RS 4\n.nf\n")
+			b.WriteString(roffEscape(strings.TrimRight(strings.TrimPrefix(line, blockIndent), " \t")) + "\n")
+			inBlock = true
+		case inBlock && indented:
+			b.WriteString(roffEscape(strings.TrimRight(strings.TrimPrefix(line, blockIndent), " \t")) + "\n")
+		default:
+			if inBlock {
+				b.WriteString(".fi\n.RE\n")
+				inBlock = false
+			}
+			b.WriteString(roffEscape(trimmed) + "\n")
+		}
+	}
+	if inBlock {
+		b.WriteString(".fi\n.RE\n")
+	}
+	return b.String()
+}
+
+// nextLineIndented reports whether the first non-blank line in lines starts with whitespace.
+func nextLineIndented(lines []string) bool {
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			return line[0] == ' ' || line[0] == '\t'
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package warg_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.bbkane.com/warg"
+	"go.bbkane.com/warg/value/scalar"
+	"go.bbkane.com/warg/value/slice"
+)
+
+func manTestApp(opts ...warg.AppOpt) warg.App {
+	return warg.New(
+		"myapp",
+		"v1.0.0",
+		warg.NewSection(
+			"Manage my app",
+			warg.SectionHelpLong("Manage my app.\n\nUse subcommands to do things."),
+			warg.SectionFooter(".Note that this line starts with a period"),
+			warg.NewSubCmd(
+				"copy",
+				"Copy files",
+				warg.Unimplemented(),
+				warg.CmdFooter("Examples:\n\n  # Copy a file\n  myapp copy a.txt b.txt\n\n  # Copy quickly\n  myapp copy --mode fast a.txt b.txt\n\nSee also myapp k8s get."),
+				warg.CmdPositional("SRC", "Source file", scalar.Path(), warg.PositionalRequired()),
+				warg.CmdPositional("DST", "Destination file", scalar.String(scalar.Default("out.txt"))),
+				warg.NewCmdFlag(
+					"--mode",
+					"Copy mode",
+					scalar.String(scalar.Choices("fast", "safe"), scalar.Default("safe")),
+					warg.ConfigPath("copy.mode"),
+					warg.FlagGroup("Behavior"),
+				),
+				warg.NewCmdFlag("--verbose", "Print each file", scalar.Bool(), warg.Switch(), warg.Alias("-v")),
+				warg.NewCmdFlag("--exclude", "Patterns to skip", slice.String(), warg.FlagDeprecated("use --ignore instead")),
+				warg.NewCmdFlag("--secret", "Hidden flag", scalar.String(), warg.FlagHidden()),
+			),
+			warg.NewSubSection(
+				"k8s",
+				"Manage Kubernetes",
+				warg.NewSectionFlag("--cluster", "Cluster to use", scalar.String(), warg.Required()),
+				warg.NewSubCmd("get", "Get resources", warg.Unimplemented(), warg.CmdDeprecated("use kubectl")),
+				warg.NewSubCmd("internal", "Hidden command", warg.Unimplemented(), warg.CmdHidden()),
+			),
+		),
+		append([]warg.AppOpt{warg.EnvPrefix("MYAPP"), warg.SkipAll()}, opts...)...,
+	)
+}
+
+func TestApp_ManPages(t *testing.T) {
+	updateGolden := os.Getenv("WARG_TEST_UPDATE_GOLDEN") != ""
+	app := manTestApp()
+
+	pages := app.ManPages()
+
+	fileNames := make([]string, 0, len(pages))
+	for _, page := range pages {
+		fileNames = append(fileNames, page.FileName)
+	}
+	require.Equal(t, []string{"myapp.1", "myapp-copy.1", "myapp-k8s-get.1"}, fileNames)
+
+	goldenDir := filepath.Join("testdata", t.Name())
+	for _, page := range pages {
+		goldenPath := filepath.Join(goldenDir, page.FileName)
+		if updateGolden {
+			require.Nil(t, os.MkdirAll(goldenDir, 0700))
+			require.Nil(t, os.WriteFile(goldenPath, []byte(page.Content), 0600))
+		}
+		expected, err := os.ReadFile(goldenPath)
+		require.Nil(t, err)
+		require.Equal(t, string(expected), page.Content, "man page %s differs from golden file", page.FileName)
+	}
+}
+
+func TestManCmd(t *testing.T) {
+	app := manTestApp(warg.ManCmd())
+	require.Nil(t, app.Validate())
+
+	dir := filepath.Join(t.TempDir(), "man1")
+	stdout, err := os.CreateTemp(t.TempDir(), "stdout")
+	require.Nil(t, err)
+	defer stdout.Close()
+
+	pr, err := app.Parse(
+		[]string{"man", "--output-dir", dir},
+		warg.ParseWithLookupEnv(warg.LookupMap(nil)),
+		warg.ParseWithStdout(stdout),
+	)
+	require.Nil(t, err)
+	require.Nil(t, pr.Action(pr.Context))
+
+	for _, page := range app.ManPages() {
+		actual, err := os.ReadFile(filepath.Join(dir, page.FileName))
+		require.Nil(t, err)
+		require.Equal(t, page.Content, string(actual))
+	}
+}
@@ -0,0 +1,132 @@
+package warg
+
+import (
+	"go.bbkane.com/warg/completion"
+	"go.bbkane.com/warg/value"
+)
+
+// PositionalOpt is a functional option for configuring a [Positional] during creation.
+type PositionalOpt func(*Positional)
+
+// NewPositional creates a [Positional] with the given name, short help text, value constructor, and options.
+// Use [CmdPositional] to simultaneously create and attach a positional argument to a [Cmd].
+func NewPositional(name string, helpShort string, empty value.EmptyConstructor, opts ...PositionalOpt) Positional {
+	pos := Positional{
+		Completions:           defaultPositionalCompletions,
+		EmptyValueConstructor: empty,
+		HelpShort:             helpShort,
+		Name:                  name,
+		Required:              false,
+		Variadic:              false,
+	}
+	for _, opt := range opts {
+		opt(&pos)
+	}
+	return pos
+}
+
+// PositionalRequired marks the positional argument as mandatory. Parsing fails if it is not passed
+// and has no default.
+func PositionalRequired() PositionalOpt {
+	return func(p *Positional) {
+		p.Required = true
+	}
+}
+
+// PositionalVariadic makes the positional argument consume all remaining positional args.
+// Only the last positional of a command may be variadic, and its value must be a slice type
+// (see the slice package).
+func PositionalVariadic() PositionalOpt {
+	return func(p *Positional) {
+		p.Variadic = true
+	}
+}
+
+// PositionalCompletions sets a custom [CompletionsFunc] for generating tab-completion candidates.
+func PositionalCompletions(completionsFunc CompletionsFunc) PositionalOpt {
+	return func(p *Positional) {
+		p.Completions = completionsFunc
+	}
+}
+
+func defaultPositionalCompletions(cmdCtx CmdContext) (*completion.Candidates, error) {
+	pos := cmdCtx.ParseState.NextPositional()
+	choices := cmdCtx.ParseState.PositionalValues[pos.Name].Choices()
+	if len(choices) > 0 {
+		candidates := &completion.Candidates{
+			Type:   completion.Type_Values,
+			Values: []completion.Candidate{},
+		}
+		for _, name := range choices {
+			candidates.Values = append(candidates.Values, completion.Candidate{
+				Name:        name,
+				Description: "",
+			})
+		}
+		return candidates, nil
+	}
+	return &completion.Candidates{
+		Type:   completion.Type_DirectoriesFiles,
+		Values: nil,
+	}, nil
+}
+
+// Positional defines a named, typed argument passed to a [Cmd] by position instead of by flag name.
+//
+// Example usage:
+//
+//	butler copy ./src.txt ./dst.txt
+type Positional struct {
+	// Completions is a function that returns a list of completion candidates for this positional.
+	Completions CompletionsFunc
+
+	// EmptyConstructor tells the positional how to make a value
+	EmptyValueConstructor value.EmptyConstructor
+
+	// HelpShort is a message for the user on how to use this positional
+	HelpShort string
+
+	// Name is used in help output and as the key in [CmdContext].Positionals. Conventionally UPPERCASE.
+	Name string
+
+	// Required means the user MUST pass this positional (or it must have a default)
+	Required bool
+
+	// Variadic means this positional consumes all remaining positional args
+	Variadic bool
+}
+
+// PositionalList holds a command's positional arguments in the order they must be passed.
+type PositionalList []Positional
+
+// Names returns the positional names in order.
+func (pl PositionalList) Names() []string {
+	names := make([]string, 0, len(pl))
+	for _, p := range pl {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// usage returns a short usage string for the positionals, such as "SRC [DST] [FILES...]".
+func (pl PositionalList) usage() string {
+	ret := ""
+	for i, p := range pl {
+		if i > 0 {
+			ret += " "
+		}
+		ret += positionalUsage(p.Name, p.Required, p.Variadic)
+	}
+	return ret
+}
+
+// positionalUsage formats one positional for a usage line, such as "SRC" or "[DSTS...]".
+func positionalUsage(name string, required bool, variadic bool) string {
+	if variadic {
+		name += "..."
+	}
+	if !required {
+		name = "[" + name + "]"
+	}
+	return name
+}
@@ -0,0 +1,81 @@
+package warg
+
+import "strings"
+
+// AllowPrefixMatching lets users type an unambiguous prefix of a section, command, or long flag
+// name (or alias, or "--no-<name>" for negatable flags) instead of the whole thing. For example, "myapp st" runs "status" if no other
+// section or command starts with "st", and "--verb" means "--verbose". Exact matches always win,
+// and hidden items are never matched by prefix.
+func AllowPrefixMatching() AppOpt {
+	return func(a *App) {
+		a.AllowPrefixMatching = true
+	}
+}
+
+// uniquePrefixMatch returns the name whose candidates are the only ones starting with prefix.
+// candidates maps each name or alias to the name it refers to.
+func uniquePrefixMatch(prefix string, candidates map[string]string) (string, bool) {
+	if prefix == "" {
+		return "", false
+	}
+	match := ""
+	for candidate, name := range candidates {
+		if !strings.HasPrefix(candidate, prefix) {
+			continue
+		}
+		if match != "" && match != name {
+			return "", false
+		}
+		match = name
+	}
+	return match, match != ""
+}
+
+// lookupPrefix finds the visible section or command that arg is an unambiguous prefix of.
+// Exact names and aliases are left for the caller to look up.
+func (sec *Section) lookupPrefix(arg string) (string, bool) {
+	if _, exists := sec.Sections.lookupName(arg); exists {
+		return "", false
+	}
+	if _, exists := sec.Cmds.lookupName(arg); exists {
+		return "", false
+	}
+	// Section and command names can't clash (see [App.Validate]), so one map holds both
+	candidates := make(map[string]string)
+	for _, name := range sec.Sections.visibleSortedNames() {
+		candidates[name] = name
+		for _, alias := range sec.Sections[name].Aliases {
+			candidates[alias] = name
+		}
+	}
+	for _, name := range sec.Cmds.visibleSortedNames() {
+		candidates[name] = name
+		for _, alias := range sec.Cmds[name].Aliases {
+			candidates[alias] = name
+		}
+	}
+	return uniquePrefixMatch(arg, candidates)
+}
+
+// lookupFlagPrefix finds the visible long flag that flagName is an unambiguous prefix of.
+// For negatable flags, a prefix of "--no-<name>" returns "--no-<name>".
+func lookupFlagPrefix(flagName string, globalFlags FlagMap, currentCommandFlags FlagMap) (string, bool) {
+	if !strings.HasPrefix(flagName, "--") || len(flagName) <= 2 {
+		return "", false
+	}
+	candidates := make(map[string]string)
+	for _, fm := range []FlagMap{globalFlags, currentCommandFlags} {
+		for _, name := range fm.visibleSortedNames() {
+			candidates[name] = name
+			for _, alias := range fm[name].helpAliases() {
+				if strings.HasPrefix(alias, "--") {
+					candidates[alias] = name
+				}
+			}
+			if fm[name].Negatable {
+				candidates[negatedName(name)] = negatedName(name)
+			}
+		}
+	}
+	return uniquePrefixMatch(flagName, candidates)
+}
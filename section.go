@@ -12,11 +12,16 @@ type SectionOpt func(*Section)
 // Attach it to a parent with [SubSection] or pass it directly to [New] as the root section.
 func NewSection(helpShort string, opts ...SectionOpt) Section {
 	section := Section{
-		HelpShort: helpShort,
-		Sections:  make(SectionMap),
-		Cmds:      make(CmdMap),
-		HelpLong:  "",
-		Footer:    "",
+		HelpShort:   helpShort,
+		Sections:    make(SectionMap),
+		Cmds:        make(CmdMap),
+		HelpLong:    "",
+		Footer:      "",
+		Deprecation: nil,
+		Hidden:      false,
+		Aliases:     nil,
+		Middlewares: nil,
+		Flags:       make(FlagMap),
 	}
 	for _, opt := range opts {
 		opt(&section)
@@ -110,6 +115,17 @@ func (fm SectionMap) SortedNames() []string {
 	return keys
 }
 
+// visibleSortedNames returns the names of sections that aren't hidden, in alphabetical order.
+func (fm SectionMap) visibleSortedNames() []string {
+	names := []string{}
+	for _, name := range fm.SortedNames() {
+		if !fm[name].Hidden {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
 // Section groups related commands and child sections, forming the hierarchical
 // structure of a CLI app. Section names should be nouns (e.g., "config", "users").
 // Do not construct directly; use [NewSection] or [NewSubSection].
@@ -124,6 +140,16 @@ type Section struct {
 	HelpLong string
 	// Footer is yet another optional longer description.
 	Footer string
+	// Deprecation marks this section as deprecated (see [SectionDeprecated])
+	Deprecation *Deprecation
+	// Hidden omits this section from help and completions (see [SectionHidden])
+	Hidden bool
+	// Aliases are alternative names for the section (see [SectionAliases])
+	Aliases []string
+	// Middlewares wrap every command in this section and its subsections (see [SectionWrap])
+	Middlewares []Middleware
+	// Flags are accepted by every command in this section and its subsections (see [NewSectionFlag])
+	Flags FlagMap
 }
 
 // flatSection represents a section and relevant parent information
@@ -155,10 +181,11 @@ func (sec *Section) breadthFirst(path []string) sectionIterator {
 // depthFirstSections returns all sections in depth-first pre-order: the current section first,
 // then each child section (sorted alphabetically) and its descendants recursively.
 // This ensures a section's own commands appear before its siblings' commands in help output.
+// Hidden child sections (and their descendants) are skipped, as this is only used for help output.
 // See https://github.com/bbkane/warg/issues/74
 func depthFirstSections(sec Section, path []string) []flatSection {
 	result := []flatSection{{Path: path, Sec: sec}}
-	for _, childName := range sec.Sections.SortedNames() {
+	for _, childName := range sec.Sections.visibleSortedNames() {
 		childPath := append(append([]string(nil), path...), childName)
 		result = append(result, depthFirstSections(sec.Sections[childName], childPath)...)
 	}
@@ -0,0 +1,110 @@
+package warg
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"go.bbkane.com/warg/value"
+)
+
+// SectionFlag attaches an existing [Flag] to a section. Every command in the section and its
+// subsections accepts the flag, as if it were declared on the command. Panics if a flag with the same name exists.
+func SectionFlag(name string, value Flag) SectionOpt {
+	return func(sec *Section) {
+		sec.Flags.AddFlag(name, value)
+	}
+}
+
+// SectionFlagMap attaches multiple existing flags to a section. Panics if any name already exists.
+func SectionFlagMap(flagMap FlagMap) SectionOpt {
+	return func(sec *Section) {
+		sec.Flags.AddFlags(flagMap)
+	}
+}
+
+// NewSectionFlag creates a new [Flag] and attaches it to a section. Every command in the section and
+// its subsections accepts the flag. Panics if a flag with the same name exists.
+//
+// Example usage:
+//
+//	warg.NewSubSection(
+//		"k8s",
+//		"Manage Kubernetes",
+//		warg.NewSectionFlag("--cluster", "Cluster to use", scalar.String(), warg.Required()),
+//		warg.NewSubCmd("get", "Get resources", get),
+//	)
+func NewSectionFlag(name string, helpShort string, empty value.EmptyConstructor, opts ...FlagOpt) SectionOpt {
+	return SectionFlag(name, NewFlag(helpShort, empty, opts...))
+}
+
+// sectionFlagGroup holds the flags declared by one section.
+type sectionFlagGroup struct {
+	// Path to the section, not including the app name. Empty for the root section
+	Path []string
+	// Flags declared by the section
+	Flags FlagMap
+}
+
+// sectionFlagGroups returns the flags declared by the root section and each section in path,
+// root first. Sections without flags are skipped.
+func (app *App) sectionFlagGroups(path []string) []sectionFlagGroup {
+	var groups []sectionFlagGroup
+	sec := app.RootSection
+	for i := 0; ; i++ {
+		if len(sec.Flags) > 0 {
+			groups = append(groups, sectionFlagGroup{
+				Path:  slices.Clone(path[:i]),
+				Flags: sec.Flags,
+			})
+		}
+		if i == len(path) {
+			return groups
+		}
+		sec = sec.Sections[path[i]]
+	}
+}
+
+// inheritFlags returns a copy of cmd whose Flags also hold the flags of the root section and each section in path.
+func (app *App) inheritFlags(path []string, cmd Cmd) Cmd {
+	groups := app.sectionFlagGroups(path)
+	if len(groups) == 0 {
+		return cmd
+	}
+	flags := maps.Clone(cmd.Flags)
+	if flags == nil {
+		flags = make(FlagMap)
+	}
+	for _, g := range groups {
+		maps.Copy(flags, g.Flags)
+	}
+	cmd.Flags = flags
+	return cmd
+}
+
+// cmdFlagEnvPath returns the path used to derive env var names (see [EnvPrefix]) for a flag of the
+// current command: the declaring section's path for inherited flags, and the command's path otherwise.
+func (app *App) cmdFlagEnvPath(ps *ParseState, flagName string) []string {
+	for _, g := range app.sectionFlagGroups(ps.SectionPath) {
+		if _, exists := g.Flags[flagName]; exists {
+			return g.Path
+		}
+	}
+	return ps.cmdPath()
+}
+
+// ownFlags returns the current command's flags that weren't inherited from a section.
+func (app *App) ownFlags(ps *ParseState) FlagMap {
+	own := maps.Clone(ps.CurrentCmd.Flags)
+	for _, g := range app.sectionFlagGroups(ps.SectionPath) {
+		for name := range g.Flags {
+			delete(own, name)
+		}
+	}
+	return own
+}
+
+// inheritedFlagsHeader returns the help header for flags inherited from the section at path, such as "Inherited Flags (myapp k8s)".
+func (app *App) inheritedFlagsHeader(path []string) string {
+	return "Inherited Flags (" + strings.Join(append([]string{app.Name}, path...), " ") + ")"
+}
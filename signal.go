@@ -0,0 +1,89 @@
+package warg
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// DefaultSignalGracePeriod is how long [App.MustRun] waits for an action to return after
+// SIGINT or SIGTERM before exiting anyway. Change it with [SignalGracePeriod].
+const DefaultSignalGracePeriod = 5 * time.Second
+
+// SignalGracePeriod sets how long [App.MustRun] and [App.MustRunWithArgs] wait for an action to
+// return after its context is cancelled by SIGINT or SIGTERM. A second signal, or the grace
+// period running out, exits immediately with code 130. Defaults to [DefaultSignalGracePeriod].
+func SignalGracePeriod(d time.Duration) AppOpt {
+	return func(a *App) {
+		a.SignalGracePeriod = d
+	}
+}
+
+// CmdHandlesSignals stops [App.MustRun] from cancelling the command's context on SIGINT or
+// SIGTERM, for interactive commands that handle signals themselves (like the "repl" command).
+func CmdHandlesSignals() CmdOpt {
+	return func(cmd *Cmd) {
+		cmd.HandlesSignals = true
+	}
+}
+
+// ParseWithContext sets the parent context passed to [CmdContext].Context. Defaults to [context.Background].
+func ParseWithContext(ctx context.Context) ParseOpt {
+	return func(poh *ParseOpts) {
+		poh.Context = ctx
+	}
+}
+
+// actionOutcome describes how an action run by runAction finished.
+type actionOutcome int
+
+const (
+	// actionOutcome_Returned means the action returned without a signal arriving.
+	actionOutcome_Returned actionOutcome = iota
+	// actionOutcome_Interrupted means a signal cancelled the action's context and the action returned within the grace period.
+	actionOutcome_Interrupted
+	// actionOutcome_Abandoned means the action didn't return within the grace period or a second signal arrived.
+	actionOutcome_Abandoned
+)
+
+// runAction runs action with cmdCtx.Context wrapped so it's cancelled when a signal arrives on sigs.
+// After the first signal, it waits up to gracePeriod for action to return. If the action is abandoned,
+// it keeps running in the background and its error is not returned.
+func runAction(action Action, cmdCtx CmdContext, gracePeriod time.Duration, sigs <-chan os.Signal) (actionOutcome, error) {
+	ctx, cancel := context.WithCancel(cmdCtx.Context)
+	defer cancel()
+	cmdCtx.Context = ctx
+
+	done := make(chan error, 1)
+	go func() {
+		done <- action(cmdCtx)
+	}()
+
+	select {
+	case err := <-done:
+		return actionOutcome_Returned, err
+	case <-sigs:
+		cancel()
+	}
+
+	timer := time.NewTimer(gracePeriod)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return actionOutcome_Interrupted, err
+	case <-sigs:
+		return actionOutcome_Abandoned, nil
+	case <-timer.C:
+		return actionOutcome_Abandoned, nil
+	}
+}
+
+// runActionWithSignals runs action with runAction, listening for SIGINT and SIGTERM while it runs.
+func runActionWithSignals(action Action, cmdCtx CmdContext, gracePeriod time.Duration) (actionOutcome, error) {
+	sigs := make(chan os.Signal, 2)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+	return runAction(action, cmdCtx, gracePeriod, sigs)
+}
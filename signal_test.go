@@ -0,0 +1,93 @@
+package warg
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.bbkane.com/warg/metadata"
+)
+
+func TestRunAction(t *testing.T) {
+	waitForCancel := func(cmdCtx CmdContext) error {
+		<-cmdCtx.Context.Done()
+		return cmdCtx.Context.Err()
+	}
+	// ignoreCancel blocks until the test finishes
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	ignoreCancel := func(cmdCtx CmdContext) error {
+		<-release
+		return nil
+	}
+
+	tests := []struct {
+		name            string
+		action          Action
+		gracePeriod     time.Duration
+		signalCount     int
+		expectedOutcome actionOutcome
+		expectedErr     error
+	}{
+		{
+			name:            "returned",
+			action:          func(CmdContext) error { return errors.New("action error") },
+			gracePeriod:     time.Minute,
+			signalCount:     0,
+			expectedOutcome: actionOutcome_Returned,
+			expectedErr:     errors.New("action error"),
+		},
+		{
+			name:            "interrupted",
+			action:          waitForCancel,
+			gracePeriod:     time.Minute,
+			signalCount:     1,
+			expectedOutcome: actionOutcome_Interrupted,
+			expectedErr:     context.Canceled,
+		},
+		{
+			name:            "abandonedAfterGracePeriod",
+			action:          ignoreCancel,
+			gracePeriod:     10 * time.Millisecond,
+			signalCount:     1,
+			expectedOutcome: actionOutcome_Abandoned,
+			expectedErr:     nil,
+		},
+		{
+			name:            "abandonedAfterSecondSignal",
+			action:          ignoreCancel,
+			gracePeriod:     time.Minute,
+			signalCount:     2,
+			expectedOutcome: actionOutcome_Abandoned,
+			expectedErr:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sigs := make(chan os.Signal, 2)
+			for range tt.signalCount {
+				sigs <- os.Interrupt
+			}
+			cmdCtx := CmdContext{
+				App:           nil,
+				Context:       context.Background(),
+				Flags:         nil,
+				ForwardedArgs: nil,
+				Positionals:   nil,
+				ParseState:    nil,
+				ParseMetadata: metadata.Empty(),
+				Stderr:        nil,
+				Stdin:         nil,
+				Stdout:        nil,
+			}
+
+			outcome, err := runAction(tt.action, cmdCtx, tt.gracePeriod, sigs)
+			require.Equal(t, tt.expectedOutcome, outcome)
+			require.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package warg
+
+import (
+	"errors"
+
+	"go.bbkane.com/warg/colerr"
+	"go.bbkane.com/warg/value"
+)
+
+// FlagValidatorFunc checks a flag's resolved value. v is the value's Get() result, so it can be
+// type asserted against the flag's underlying type (for example, v.(int) for a scalar.Int flag).
+type FlagValidatorFunc func(v any) error
+
+// CmdValidatorFunc checks a command's resolved flags and positionals together.
+type CmdValidatorFunc func(CmdContext) error
+
+// FlagValidator adds a validator that [App.Parse] runs on the flag's resolved value. Validators are
+// only run if the flag is set (from any source, including its default). Unlike the value's
+// FromString, validators run for values from every source.
+//
+// Example usage:
+//
+//	warg.FlagValidator(func(v any) error {
+//		if v.(int) > 65535 {
+//			return errors.New("port must be <= 65535")
+//		}
+//		return nil
+//	})
+func FlagValidator(validator FlagValidatorFunc) FlagOpt {
+	return func(f *Flag) {
+		f.Validators = append(f.Validators, validator)
+	}
+}
+
+// CmdValidator adds a validator that [App.Parse] runs after all flags and positionals are resolved.
+// Use it for checks that involve several flags. It doesn't run if required flags or positionals are
+// missing or flag constraints are violated, so it can assume required values are present.
+func CmdValidator(validator CmdValidatorFunc) CmdOpt {
+	return func(cmd *Cmd) {
+		cmd.Validators = append(cmd.Validators, validator)
+	}
+}
+
+// runValidators checks the constraints (see [value.ConstrainedValue]) and runs the validators of every
+// set flag (global flags first, then command flags, each in sorted order), then checks the constraints
+// of set positionals, and then runs the command's validators. errs holds problems [App.Parse] already
+// found (missing required flags and positionals, and violated flag constraints and rules). All failures
+// are joined so they can be reported at once. Command validators can assume required flags and
+// positionals are present, so they only run if errs is empty.
+func runValidators(cmdCtx CmdContext, errs []error) error {
+	ps := cmdCtx.ParseState
+	runCmdValidators := len(errs) == 0
+	for _, fm := range []FlagMap{cmdCtx.App.GlobalFlags, ps.CurrentCmd.Flags} {
+		for _, name := range fm.SortedNames() {
+			if !ps.FlagValues.IsSet(name) {
+				continue
+			}
+			if cv, ok := ps.FlagValues[name].(value.ConstrainedValue); ok {
+				err := cv.CheckConstraints()
+				if err != nil {
+					errs = append(errs, colerr.NewWrappedf(err, "Invalid value for flag: %s", describeFlagSource(name, ps.FlagSources)))
+				}
+			}
+			for _, validator := range fm[name].Validators {
+				err := validator(ps.FlagValues[name].Get())
+				if err != nil {
+					errs = append(errs, colerr.NewWrappedf(err, "Invalid value for flag: %s", describeFlagSource(name, ps.FlagSources)))
+				}
+			}
+		}
+	}
+	for _, pos := range ps.CurrentCmd.Positionals {
+		if !ps.PositionalValues.IsSet(pos.Name) {
+			continue
+		}
+		if cv, ok := ps.PositionalValues[pos.Name].(value.ConstrainedValue); ok {
+			err := cv.CheckConstraints()
+			if err != nil {
+				errs = append(errs, colerr.NewWrappedf(err, "Invalid value for positional: %s", pos.Name))
+			}
+		}
+	}
+	if runCmdValidators {
+		for _, validator := range ps.CurrentCmd.Validators {
+			err := validator(cmdCtx)
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	err := errors.Join(errs...)
+	if err != nil {
+		return colerr.NewWrapped(err, "Validation failed")
+	}
+	return nil
+}
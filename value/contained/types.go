@@ -190,11 +190,14 @@ func DateTimeRFC3339() TypeInfo[time.Time] {
 		Description: "datetime in RFC3339 format",
 		FromZero:    FromZero[time.Time],
 		FromIFace: func(iFace interface{}) (time.Time, error) {
-			under, ok := iFace.(string)
-			if !ok {
+			switch under := iFace.(type) {
+			case string:
+				return time.Parse(time.RFC3339, under)
+			case time.Time: // TOML has a native datetime type
+				return under, nil
+			default:
 				return time.Time{}, ErrIncompatibleInterface
 			}
-			return time.Parse(time.RFC3339, under)
 		},
 		FromString: func(s string) (time.Time, error) {
 			return time.Parse(time.RFC3339, s)
@@ -0,0 +1,84 @@
+package dict
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.bbkane.com/warg/value"
+)
+
+type constraint[T any] struct {
+	description string
+	check       func(vals map[string]T) bool
+	// resolvedOnly constraints can't be checked as entries are added (e.g. required keys),
+	// so they're only checked by ReplaceFromInterface and CheckConstraints
+	resolvedOnly bool
+}
+
+// RequiredKeys requires the dict to contain each key once resolved. T can't be inferred from the keys,
+// so it must be passed explicitly.
+//
+// Example usage:
+//
+//	dict.String(dict.RequiredKeys[string]("name"))
+func RequiredKeys[T any](keys ...string) DictOpt[T] {
+	return func(v *dictValue[T]) {
+		v.constraints = append(v.constraints, constraint[T]{
+			description: "requiredkeys: " + strings.Join(keys, ", "),
+			check: func(vals map[string]T) bool {
+				for _, k := range keys {
+					if _, exists := vals[k]; !exists {
+						return false
+					}
+				}
+				return true
+			},
+			resolvedOnly: true,
+		})
+	}
+}
+
+// AllowedKeys requires every key in the dict to be one of keys. T can't be inferred from the keys,
+// so it must be passed explicitly.
+func AllowedKeys[T any](keys ...string) DictOpt[T] {
+	return func(v *dictValue[T]) {
+		v.constraints = append(v.constraints, constraint[T]{
+			description: "allowedkeys: " + strings.Join(keys, ", "),
+			check: func(vals map[string]T) bool {
+				for k := range vals {
+					if !slices.Contains(keys, k) {
+						return false
+					}
+				}
+				return true
+			},
+			resolvedOnly: false,
+		})
+	}
+}
+
+// checkConstraints checks vals against the constraints. Set resolved to also check resolvedOnly constraints.
+func (v *dictValue[T]) checkConstraints(vals map[string]T, resolved bool) error {
+	for _, c := range v.constraints {
+		if c.resolvedOnly && !resolved {
+			continue
+		}
+		if !c.check(vals) {
+			return value.ErrConstraint{Constraint: c.description, Value: fmt.Sprint(vals)}
+		}
+	}
+	return nil
+}
+
+func (v *dictValue[_]) Constraints() []string {
+	ret := make([]string, 0, len(v.constraints))
+	for _, c := range v.constraints {
+		ret = append(ret, c.description)
+	}
+	return ret
+}
+
+func (v *dictValue[_]) CheckConstraints() error {
+	return v.checkConstraints(v.vals, true)
+}
@@ -2,6 +2,7 @@ package dict
 
 import (
 	"fmt"
+	"maps"
 	"strings"
 
 	"go.bbkane.com/warg/colerr"
@@ -11,6 +12,7 @@ import (
 
 type dictValue[T any] struct {
 	choices     []T
+	constraints []constraint[T]
 	defaultVals map[string]T
 	hasDefault  bool
 	inner       contained.TypeInfo[T]
@@ -27,6 +29,7 @@ func New[T any](inner contained.TypeInfo[T], opts ...DictOpt[T]) value.EmptyCons
 	return func() value.Value {
 		dv := dictValue[T]{
 			choices:     []T{},
+			constraints: nil,
 			defaultVals: make(map[string]T),
 			hasDefault:  false,
 			inner:       inner,
@@ -98,6 +101,10 @@ func (v *dictValue[T]) ReplaceFromInterface(iFace interface{}, u value.UpdatedBy
 		}
 		newVals[k] = underE
 	}
+	err := v.checkConstraints(newVals, true)
+	if err != nil {
+		return err
+	}
 	v.vals = newVals
 	v.updatedBy = u
 	return nil
@@ -115,7 +122,16 @@ func (v *dictValue[T]) update(key string, val T) error {
 	if !contained.WithinChoices(val, v.choices, v.inner.Equals) {
 		return value.ErrInvalidChoice[T]{Choices: v.choices}
 	}
-	v.vals[key] = val
+	newVals := maps.Clone(v.vals)
+	if newVals == nil {
+		newVals = make(map[string]T)
+	}
+	newVals[key] = val
+	err := v.checkConstraints(newVals, false)
+	if err != nil {
+		return err
+	}
+	v.vals = newVals
 	return nil
 }
 
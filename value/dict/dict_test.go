@@ -81,3 +81,22 @@ func TestDict_Update(t *testing.T) {
 	actual := dictVal.Get().(map[string]netip.Addr)
 	require.Equal(t, expected, actual)
 }
+
+func TestDict_Constraints(t *testing.T) {
+	constructor := dict.Int(dict.RequiredKeys[int]("a"), dict.AllowedKeys[int]("a", "b"))
+
+	v := constructor()
+	require.Equal(t, []string{"requiredkeys: a", "allowedkeys: a, b"}, value.Constraints(v))
+
+	require.NoError(t, v.Update("b=2", value.UpdatedByFlag))
+	cv := v.(value.ConstrainedValue)
+	require.Equal(t, value.ErrConstraint{Constraint: "requiredkeys: a", Value: "map[b:2]"}, cv.CheckConstraints())
+
+	require.Equal(t, value.ErrConstraint{Constraint: "allowedkeys: a, b", Value: "map[b:2 c:3]"}, v.Update("c=3", value.UpdatedByFlag))
+	require.NoError(t, v.Update("a=1", value.UpdatedByFlag))
+	require.NoError(t, cv.CheckConstraints())
+
+	v = constructor()
+	err := v.ReplaceFromInterface(map[string]interface{}{"b": 2}, value.UpdatedByConfig)
+	require.Equal(t, value.ErrConstraint{Constraint: "requiredkeys: a", Value: "map[b:2]"}, err)
+}
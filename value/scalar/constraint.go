@@ -0,0 +1,70 @@
+package scalar
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+
+	"go.bbkane.com/warg/value"
+)
+
+type constraint[T any] struct {
+	description string
+	check       func(T) bool
+}
+
+// Min requires the value to be greater than or equal to minimum.
+func Min[T cmp.Ordered](minimum T) ScalarOpt[T] {
+	return func(v *scalarValue[T]) {
+		v.constraints = append(v.constraints, constraint[T]{
+			description: "min: " + fmt.Sprint(minimum),
+			check:       func(val T) bool { return cmp.Compare(val, minimum) >= 0 },
+		})
+	}
+}
+
+// Max requires the value to be less than or equal to maximum.
+func Max[T cmp.Ordered](maximum T) ScalarOpt[T] {
+	return func(v *scalarValue[T]) {
+		v.constraints = append(v.constraints, constraint[T]{
+			description: "max: " + fmt.Sprint(maximum),
+			check:       func(val T) bool { return cmp.Compare(val, maximum) <= 0 },
+		})
+	}
+}
+
+// Regex requires a string value to match the pattern. Panics if the pattern doesn't compile.
+//
+// Example usage:
+//
+//	scalar.String(scalar.Regex(`^[a-z][a-z0-9-]*$`))
+func Regex(pattern string) ScalarOpt[string] {
+	re := regexp.MustCompile(pattern)
+	return func(v *scalarValue[string]) {
+		v.constraints = append(v.constraints, constraint[string]{
+			description: "pattern: " + pattern,
+			check:       re.MatchString,
+		})
+	}
+}
+
+func (v *scalarValue[T]) checkConstraints(val T) error {
+	for _, c := range v.constraints {
+		if !c.check(val) {
+			return value.ErrConstraint{Constraint: c.description, Value: fmt.Sprint(val)}
+		}
+	}
+	return nil
+}
+
+func (v *scalarValue[_]) Constraints() []string {
+	ret := make([]string, 0, len(v.constraints))
+	for _, c := range v.constraints {
+		ret = append(ret, c.description)
+	}
+	return ret
+}
+
+func (v *scalarValue[_]) CheckConstraints() error {
+	return v.checkConstraints(*v.val)
+}
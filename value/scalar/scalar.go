@@ -8,11 +8,12 @@ import (
 )
 
 type scalarValue[T any] struct {
-	choices    []T
-	defaultVal *T
-	inner      contained.TypeInfo[T]
-	val        *T
-	updatedBy  value.UpdatedBy
+	choices     []T
+	constraints []constraint[T]
+	defaultVal  *T
+	inner       contained.TypeInfo[T]
+	val         *T
+	updatedBy   value.UpdatedBy
 }
 
 // ScalarOpt is a functional option for configuring a scalar value.
@@ -24,11 +25,12 @@ func newScalarValue[T any](
 ) scalarValue[T] {
 	empty := inner.FromZero()
 	sv := scalarValue[T]{
-		choices:    []T{},
-		defaultVal: nil,
-		inner:      inner,
-		val:        &empty,
-		updatedBy:  value.UpdatedByUnset,
+		choices:     []T{},
+		constraints: nil,
+		defaultVal:  nil,
+		inner:       inner,
+		val:         &empty,
+		updatedBy:   value.UpdatedByUnset,
 	}
 	for _, opt := range opts {
 		opt(&sv)
@@ -107,6 +109,10 @@ func (v *scalarValue[T]) ReplaceFromInterface(iFace interface{}, u value.Updated
 	if err != nil {
 		return err
 	}
+	err = v.checkConstraints(val)
+	if err != nil {
+		return err
+	}
 	*v.val = val
 	v.updatedBy = u
 	return nil
@@ -128,6 +134,10 @@ func (v *scalarValue[T]) Update(s string, u value.UpdatedBy) error {
 	if !contained.WithinChoices(val, v.choices, v.inner.Equals) {
 		return value.ErrInvalidChoice[T]{Choices: v.choices}
 	}
+	err = v.checkConstraints(val)
+	if err != nil {
+		return err
+	}
 	*v.val = val
 	v.updatedBy = u
 	return nil
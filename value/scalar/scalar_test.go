@@ -32,3 +32,49 @@ func TestDefaultAndChoices(t *testing.T) {
 	actualChoices := v.Choices()
 	require.Equal(t, []string{"1", "2"}, actualChoices)
 }
+
+func TestConstraints(t *testing.T) {
+	tests := []struct {
+		name        string
+		constructor value.EmptyConstructor
+		update      string
+		expectedErr error
+	}{
+		{
+			name:        "withinRange",
+			constructor: scalar.Int(scalar.Min(1), scalar.Max(10)),
+			update:      "10",
+			expectedErr: nil,
+		},
+		{
+			name:        "belowMin",
+			constructor: scalar.Int(scalar.Min(1), scalar.Max(10)),
+			update:      "0",
+			expectedErr: value.ErrConstraint{Constraint: "min: 1", Value: "0"},
+		},
+		{
+			name:        "aboveMax",
+			constructor: scalar.Duration(scalar.Max(time.Minute)),
+			update:      "2m",
+			expectedErr: value.ErrConstraint{Constraint: "max: 1m0s", Value: "2m0s"},
+		},
+		{
+			name:        "regexMismatch",
+			constructor: scalar.String(scalar.Regex(`^[a-z]+$`)),
+			update:      "ABC",
+			expectedErr: value.ErrConstraint{Constraint: "pattern: ^[a-z]+$", Value: "ABC"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := tt.constructor()
+			err := v.Update(tt.update, value.UpdatedByFlag)
+			require.Equal(t, tt.expectedErr, err)
+		})
+	}
+
+	v := scalar.Int(scalar.Min(1), scalar.Max(10))()
+	require.Equal(t, []string{"min: 1", "max: 10"}, value.Constraints(v))
+	err := v.ReplaceFromInterface(11, value.UpdatedByConfig)
+	require.Equal(t, value.ErrConstraint{Constraint: "max: 10", Value: "11"}, err)
+}
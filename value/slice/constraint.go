@@ -0,0 +1,93 @@
+package slice
+
+import (
+	"fmt"
+	"strconv"
+
+	"go.bbkane.com/warg/value"
+)
+
+type constraint[T any] struct {
+	description string
+	check       func(inner func(a, b T) bool, vals []T) bool
+	// resolvedOnly constraints can't be checked as elements are appended (e.g. a minimum length),
+	// so they're only checked by ReplaceFromInterface and CheckConstraints
+	resolvedOnly bool
+}
+
+// MinLen requires the slice to have at least n elements once resolved. T can't be inferred from n,
+// so it must be passed explicitly.
+//
+// Example usage:
+//
+//	slice.String(slice.MinLen[string](1))
+func MinLen[T any](n int) SliceOpt[T] {
+	return func(v *sliceValue[T]) {
+		v.constraints = append(v.constraints, constraint[T]{
+			description:  "minlen: " + strconv.Itoa(n),
+			check:        func(_ func(a, b T) bool, vals []T) bool { return len(vals) >= n },
+			resolvedOnly: true,
+		})
+	}
+}
+
+// MaxLen requires the slice to have at most n elements. T can't be inferred from n,
+// so it must be passed explicitly.
+func MaxLen[T any](n int) SliceOpt[T] {
+	return func(v *sliceValue[T]) {
+		v.constraints = append(v.constraints, constraint[T]{
+			description:  "maxlen: " + strconv.Itoa(n),
+			check:        func(_ func(a, b T) bool, vals []T) bool { return len(vals) <= n },
+			resolvedOnly: false,
+		})
+	}
+}
+
+// Unique requires the slice elements to be unique. T can't be inferred, so it must be passed explicitly.
+//
+// Example usage:
+//
+//	slice.String(slice.Unique[string]())
+func Unique[T any]() SliceOpt[T] {
+	return func(v *sliceValue[T]) {
+		v.constraints = append(v.constraints, constraint[T]{
+			description: "unique",
+			check: func(equals func(a, b T) bool, vals []T) bool {
+				for i := range vals {
+					for j := i + 1; j < len(vals); j++ {
+						if equals(vals[i], vals[j]) {
+							return false
+						}
+					}
+				}
+				return true
+			},
+			resolvedOnly: false,
+		})
+	}
+}
+
+// checkConstraints checks vals against the constraints. Set resolved to also check resolvedOnly constraints.
+func (v *sliceValue[T]) checkConstraints(vals []T, resolved bool) error {
+	for _, c := range v.constraints {
+		if c.resolvedOnly && !resolved {
+			continue
+		}
+		if !c.check(v.inner.Equals, vals) {
+			return value.ErrConstraint{Constraint: c.description, Value: fmt.Sprint(vals)}
+		}
+	}
+	return nil
+}
+
+func (v *sliceValue[_]) Constraints() []string {
+	ret := make([]string, 0, len(v.constraints))
+	for _, c := range v.constraints {
+		ret = append(ret, c.description)
+	}
+	return ret
+}
+
+func (v *sliceValue[_]) CheckConstraints() error {
+	return v.checkConstraints(v.vals, true)
+}
@@ -2,6 +2,7 @@ package slice
 
 import (
 	"fmt"
+	"slices"
 
 	value "go.bbkane.com/warg/value"
 	"go.bbkane.com/warg/value/contained"
@@ -9,6 +10,7 @@ import (
 
 type sliceValue[T any] struct {
 	choices     []T
+	constraints []constraint[T]
 	defaultVals []T
 	hasDefault  bool
 	inner       contained.TypeInfo[T]
@@ -25,6 +27,7 @@ func New[T any](hc contained.TypeInfo[T], opts ...SliceOpt[T]) value.EmptyConstr
 	return func() value.Value {
 		sv := sliceValue[T]{
 			choices:     []T{},
+			constraints: nil,
 			defaultVals: nil,
 			hasDefault:  false,
 			inner:       hc,
@@ -98,6 +101,10 @@ func (v *sliceValue[T]) ReplaceFromInterface(iFace interface{}, u value.UpdatedB
 		}
 		newVals = append(newVals, underE)
 	}
+	err := v.checkConstraints(newVals, true)
+	if err != nil {
+		return err
+	}
 	v.updatedBy = u
 	v.vals = newVals
 	return nil
@@ -115,7 +122,12 @@ func (v *sliceValue[T]) update(val T) error {
 	if !contained.WithinChoices(val, v.choices, v.inner.Equals) {
 		return value.ErrInvalidChoice[T]{Choices: v.choices}
 	}
-	v.vals = append(v.vals, val)
+	newVals := append(slices.Clone(v.vals), val)
+	err := v.checkConstraints(newVals, false)
+	if err != nil {
+		return err
+	}
+	v.vals = newVals
 	return nil
 }
 
@@ -28,3 +28,26 @@ func TestDefaultAndChoices(t *testing.T) {
 	actualChoices := v.Choices()
 	require.Equal(t, []string{"1", "2"}, actualChoices)
 }
+
+func TestConstraints(t *testing.T) {
+	constructor := slice.String(slice.MinLen[string](2), slice.MaxLen[string](3), slice.Unique[string]())
+
+	v := constructor()
+	require.Equal(t, []string{"minlen: 2", "maxlen: 3", "unique"}, value.Constraints(v))
+
+	// MinLen is only checked once the value is resolved
+	require.NoError(t, v.Update("a", value.UpdatedByFlag))
+	cv := v.(value.ConstrainedValue)
+	require.Equal(t, value.ErrConstraint{Constraint: "minlen: 2", Value: "[a]"}, cv.CheckConstraints())
+
+	require.Equal(t, value.ErrConstraint{Constraint: "unique", Value: "[a a]"}, v.Update("a", value.UpdatedByFlag))
+	require.NoError(t, v.Update("b", value.UpdatedByFlag))
+	require.NoError(t, v.Update("c", value.UpdatedByFlag))
+	require.Equal(t, value.ErrConstraint{Constraint: "maxlen: 3", Value: "[a b c d]"}, v.Update("d", value.UpdatedByFlag))
+	require.Equal(t, []string{"a", "b", "c"}, v.Get())
+	require.NoError(t, cv.CheckConstraints())
+
+	v = constructor()
+	err := v.ReplaceFromInterface([]interface{}{"a"}, value.UpdatedByConfig)
+	require.Equal(t, value.ErrConstraint{Constraint: "minlen: 2", Value: "[a]"}, err)
+}
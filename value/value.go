@@ -12,11 +12,12 @@ import (
 type UpdatedBy string
 
 const (
-	UpdatedByUnset   UpdatedBy = ""
-	UpdatedByDefault UpdatedBy = "appdefault"
-	UpdatedByEnvVar  UpdatedBy = "envvar"
-	UpdatedByFlag    UpdatedBy = "passedflag"
-	UpdatedByConfig  UpdatedBy = "config"
+	UpdatedByUnset      UpdatedBy = ""
+	UpdatedByDefault    UpdatedBy = "appdefault"
+	UpdatedByEnvVar     UpdatedBy = "envvar"
+	UpdatedByFlag       UpdatedBy = "passedflag"
+	UpdatedByPositional UpdatedBy = "passedpositional"
+	UpdatedByConfig     UpdatedBy = "config"
 )
 
 // Value is the interface for all flag value types (scalar, slice, dict).
@@ -85,6 +86,28 @@ type DictValue interface {
 	StringMap() map[string]string
 }
 
+// ConstrainedValue is implemented by [Value]s that support constraints beyond Choices, such as
+// scalar.Min or slice.MaxLen. Update and ReplaceFromInterface enforce the constraints that can be
+// checked as values are added; CheckConstraints enforces the rest once the value is resolved.
+type ConstrainedValue interface {
+	Value
+
+	// Constraints describes each constraint for help output, such as "min: 1" or "pattern: ^[a-z]+$"
+	Constraints() []string
+
+	// CheckConstraints checks the resolved value against all constraints
+	CheckConstraints() error
+}
+
+// Constraints returns the constraint descriptions of v if it's a [ConstrainedValue], and nil otherwise.
+func Constraints(v Value) []string {
+	cv, ok := v.(ConstrainedValue)
+	if !ok {
+		return nil
+	}
+	return cv.Constraints()
+}
+
 // EmptyConstructor creates a new zero-valued [Value] instance.
 // Used both for initialization and to produce fresh values during parsing.
 type EmptyConstructor func() Value
@@ -109,6 +132,21 @@ func (e ErrInvalidChoice[T]) ColorError(s *styles.Styles) string {
 	return s.Error(buf.String())
 }
 
+// ErrConstraint is returned when a value doesn't satisfy one of its constraints (see [ConstrainedValue]).
+type ErrConstraint struct {
+	Constraint string
+	Value      string
+}
+
+func (e ErrConstraint) Error() string {
+	return "value " + e.Value + " does not satisfy constraint: " + e.Constraint
+}
+
+func (e ErrConstraint) ColorError(s *styles.Styles) string {
+	err := colerr.NewWrappedf(nil, "Value %s does not satisfy constraint: %s", e.Value, e.Constraint)
+	return err.ColorError(s)
+}
+
 // ErrUpdatedMoreThanOnce is returned when a scalar value is set more than once
 // from the same priority level (e.g., two CLI flags for the same scalar).
 type ErrUpdatedMoreThanOnce[T any] struct {